@@ -0,0 +1,315 @@
+// Package accounting aggregates validator rewards, slashes and unbonds from node events over a range of heights
+// and exports the totals as CSV or JSON.
+package accounting
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/nikolaev-dev/sdk/api"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Total of rewards of a role paid to address for validator.
+type RewardTotal struct {
+	Role            string   `json:"role"`
+	Address         string   `json:"address"`
+	ValidatorPubKey string   `json:"validator_pub_key"`
+	Count           int      `json:"count"`
+	Amount          *big.Int `json:"amount"`
+}
+
+// Total of slashes or unbonds in coin.
+type CoinTotal struct {
+	Coin   string   `json:"coin"`
+	Count  int      `json:"count"`
+	Amount *big.Int `json:"amount"`
+}
+
+type rewardKey struct {
+	role, address, validator string
+}
+
+// Report of events aggregated over heights From..To. It is safe for concurrent use.
+type Report struct {
+	From int
+	To   int
+
+	mu      sync.Mutex
+	rewards map[rewardKey]*RewardTotal
+	slashes map[string]*CoinTotal
+	unbonds map[string]*CoinTotal
+}
+
+// Create empty report of heights from..to.
+func NewReport(from, to int) *Report {
+	return &Report{
+		From:    from,
+		To:      to,
+		rewards: make(map[rewardKey]*RewardTotal),
+		slashes: make(map[string]*CoinTotal),
+		unbonds: make(map[string]*CoinTotal),
+	}
+}
+
+// Add reward, slash or unbond event to the totals, events of other types are ignored.
+func (r *Report) AddEvent(event *api.Event) error {
+	switch event.Type {
+	case api.EventTypeReward, api.EventTypeSlash, api.EventTypeUnbond:
+	default:
+		return nil
+	}
+	value, err := event.ValueStruct()
+	if err != nil {
+		return err
+	}
+	amountValue, ok := value.(api.AmountEventValue)
+	if !ok {
+		return fmt.Errorf("value %T of %s has no amount", value, event.Type)
+	}
+	amount, err := amountValue.AmountInt()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch v := value.(type) {
+	case *api.RewardEventValue:
+		key := rewardKey{role: v.Role, address: v.Address, validator: v.ValidatorPubKey}
+		total, ok := r.rewards[key]
+		if !ok {
+			total = &RewardTotal{Role: v.Role, Address: v.Address, ValidatorPubKey: v.ValidatorPubKey, Amount: new(big.Int)}
+			r.rewards[key] = total
+		}
+		total.Count++
+		total.Amount.Add(total.Amount, amount)
+	case *api.SlashEventValue:
+		addCoinTotal(r.slashes, v.Coin, amount)
+	case *api.UnbondEventValue:
+		addCoinTotal(r.unbonds, v.Coin, amount)
+	default:
+		return fmt.Errorf("unsupported value %T of %s", value, event.Type)
+	}
+	return nil
+}
+
+func addCoinTotal(totals map[string]*CoinTotal, coin string, amount *big.Int) {
+	total, ok := totals[coin]
+	if !ok {
+		total = &CoinTotal{Coin: coin, Amount: new(big.Int)}
+		totals[coin] = total
+	}
+	total.Count++
+	total.Amount.Add(total.Amount, amount)
+}
+
+// Returns reward totals sorted by role, address and validator.
+func (r *Report) Rewards() []*RewardTotal {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rewards := make([]*RewardTotal, 0, len(r.rewards))
+	for _, total := range r.rewards {
+		c := *total
+		c.Amount = new(big.Int).Set(total.Amount)
+		rewards = append(rewards, &c)
+	}
+	sort.Slice(rewards, func(i, j int) bool {
+		a, b := rewards[i], rewards[j]
+		if a.Role != b.Role {
+			return a.Role < b.Role
+		}
+		if a.Address != b.Address {
+			return a.Address < b.Address
+		}
+		return a.ValidatorPubKey < b.ValidatorPubKey
+	})
+	return rewards
+}
+
+// Returns slash totals sorted by coin.
+func (r *Report) Slashes() []*CoinTotal {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return sortedCoinTotals(r.slashes)
+}
+
+// Returns unbond totals sorted by coin.
+func (r *Report) Unbonds() []*CoinTotal {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return sortedCoinTotals(r.unbonds)
+}
+
+func sortedCoinTotals(totals map[string]*CoinTotal) []*CoinTotal {
+	result := make([]*CoinTotal, 0, len(totals))
+	for _, total := range totals {
+		c := *total
+		c.Amount = new(big.Int).Set(total.Amount)
+		result = append(result, &c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Coin < result[j].Coin })
+	return result
+}
+
+// Returns sum of all rewards.
+func (r *Report) TotalRewards() *big.Int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sum := new(big.Int)
+	for _, total := range r.rewards {
+		sum.Add(sum, total.Amount)
+	}
+	return sum
+}
+
+// Write report as CSV with header "kind,role,address,validator_pub_key,coin,count,amount",
+// kind is "reward", "slash" or "unbond", amounts are integers in pip.
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	records := [][]string{{"kind", "role", "address", "validator_pub_key", "coin", "count", "amount"}}
+	for _, total := range r.Rewards() {
+		records = append(records, []string{"reward", total.Role, total.Address, total.ValidatorPubKey, "", strconv.Itoa(total.Count), total.Amount.String()})
+	}
+	for _, total := range r.Slashes() {
+		records = append(records, []string{"slash", "", "", "", total.Coin, strconv.Itoa(total.Count), total.Amount.String()})
+	}
+	for _, total := range r.Unbonds() {
+		records = append(records, []string{"unbond", "", "", "", total.Coin, strconv.Itoa(total.Count), total.Amount.String()})
+	}
+	return writer.WriteAll(records)
+}
+
+type reportJSON struct {
+	From         int            `json:"from"`
+	To           int            `json:"to"`
+	TotalRewards string         `json:"total_rewards"`
+	Rewards      []*RewardTotal `json:"rewards"`
+	Slashes      []*CoinTotal   `json:"slashes"`
+	Unbonds      []*CoinTotal   `json:"unbonds"`
+}
+
+// Encode report as JSON, amounts are encoded as strings to keep precision in JSON decoders using floats.
+func (r *Report) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&reportJSON{
+		From:         r.From,
+		To:           r.To,
+		TotalRewards: r.TotalRewards().String(),
+		Rewards:      r.Rewards(),
+		Slashes:      r.Slashes(),
+		Unbonds:      r.Unbonds(),
+	})
+}
+
+// Write report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func (t *RewardTotal) MarshalJSON() ([]byte, error) {
+	type total RewardTotal
+	return json.Marshal(&struct {
+		*total
+		Amount string `json:"amount"`
+	}{total: (*total)(t), Amount: t.Amount.String()})
+}
+
+func (t *CoinTotal) MarshalJSON() ([]byte, error) {
+	type total CoinTotal
+	return json.Marshal(&struct {
+		*total
+		Amount string `json:"amount"`
+	}{total: (*total)(t), Amount: t.Amount.String()})
+}
+
+// Option configures Scan.
+type Option func(*scanOptions)
+
+type scanOptions struct {
+	concurrency int
+}
+
+// Set number of heights requested in parallel, 1 by default.
+func WithConcurrency(n int) Option {
+	return func(o *scanOptions) {
+		o.concurrency = n
+	}
+}
+
+// Scan events of heights from..to and aggregate them into report. It stops on the first error.
+func Scan(ctx context.Context, node api.NodeClient, from, to int, opts ...Option) (*Report, error) {
+	o := &scanOptions{concurrency: 1}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.concurrency < 1 {
+		o.concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	report := NewReport(from, to)
+	heights := make(chan int)
+	errs := make(chan error, o.concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < o.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for height := range heights {
+				err := scanHeight(node, report, height)
+				if err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+loop:
+	for height := from; height <= to; height++ {
+		select {
+		case <-ctx.Done():
+			break loop
+		case heights <- height:
+		}
+	}
+	close(heights)
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		return nil, err
+	default:
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func scanHeight(node api.NodeClient, report *Report, height int) error {
+	result, err := node.EventsAtHeight(height)
+	if err != nil {
+		return err
+	}
+	for i := range result.Events {
+		err = report.AddEvent(&result.Events[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
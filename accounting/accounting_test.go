@@ -0,0 +1,125 @@
+package accounting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/nikolaev-dev/sdk/api"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type fakeNode struct {
+	api.NodeClient
+	fail int
+}
+
+// Every height has DAO and validator rewards, even heights have slash, heights divisible by 3 have unbond.
+func (n *fakeNode) EventsAtHeight(height int) (*api.EventsResult, error) {
+	if height == n.fail {
+		return nil, errors.New("node error")
+	}
+	events := []api.Event{
+		{Type: api.EventTypeReward, Value: map[string]string{"role": "DAO", "address": "Mx01", "amount": "100000000000000000000000", "validator_pub_key": "Mp01"}},
+		{Type: api.EventTypeReward, Value: map[string]string{"role": "Validator", "address": "Mx02", "amount": strconv.Itoa(height), "validator_pub_key": "Mp01"}},
+		{Type: api.EventTypeCoinLiquidation, Value: map[string]string{"coin": "DEAD"}},
+	}
+	if height%2 == 0 {
+		events = append(events, api.Event{Type: api.EventTypeSlash, Value: map[string]string{"address": "Mx03", "amount": "7", "coin": "MNT", "validator_pub_key": "Mp01"}})
+	}
+	if height%3 == 0 {
+		events = append(events, api.Event{Type: api.EventTypeUnbond, Value: map[string]string{"address": "Mx03", "amount": "5", "coin": "TEST", "validator_pub_key": "Mp01"}})
+	}
+	return &api.EventsResult{Events: events}, nil
+}
+
+func TestScan(t *testing.T) {
+	report, err := Scan(context.Background(), &fakeNode{}, 1, 10, WithConcurrency(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rewards := report.Rewards()
+	if len(rewards) != 2 {
+		t.Fatalf("rewards want 2, got %d", len(rewards))
+	}
+	if rewards[0].Role != "DAO" || rewards[0].Count != 10 || rewards[0].Amount.String() != "1000000000000000000000000" {
+		t.Errorf("unexpected DAO reward %+v", rewards[0])
+	}
+	if rewards[1].Role != "Validator" || rewards[1].Amount.Int64() != 55 {
+		t.Errorf("unexpected validator reward %+v", rewards[1])
+	}
+	if report.TotalRewards().String() != "1000000000000000000000055" {
+		t.Errorf("unexpected total rewards %s", report.TotalRewards())
+	}
+	if slashes := report.Slashes(); len(slashes) != 1 || slashes[0].Coin != "MNT" || slashes[0].Count != 5 || slashes[0].Amount.Int64() != 35 {
+		t.Errorf("unexpected slashes %+v", slashes)
+	}
+	if unbonds := report.Unbonds(); len(unbonds) != 1 || unbonds[0].Coin != "TEST" || unbonds[0].Count != 3 || unbonds[0].Amount.Int64() != 15 {
+		t.Errorf("unexpected unbonds %+v", unbonds)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := strings.Join([]string{
+		"kind,role,address,validator_pub_key,coin,count,amount",
+		"reward,DAO,Mx01,Mp01,,10,1000000000000000000000000",
+		"reward,Validator,Mx02,Mp01,,10,55",
+		"slash,,,,MNT,5,35",
+		"unbond,,,,TEST,3,15",
+		"",
+	}, "\n")
+	if buf.String() != wantCSV {
+		t.Errorf("CSV want\n%s\ngot\n%s", wantCSV, buf.String())
+	}
+
+	buf.Reset()
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		From         int    `json:"from"`
+		To           int    `json:"to"`
+		TotalRewards string `json:"total_rewards"`
+		Rewards      []struct {
+			Role   string `json:"role"`
+			Amount string `json:"amount"`
+		} `json:"rewards"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.From != 1 || decoded.To != 10 || decoded.TotalRewards != "1000000000000000000000055" || decoded.Rewards[0].Amount != "1000000000000000000000000" {
+		t.Errorf("unexpected JSON %s", buf.String())
+	}
+}
+
+func TestScan_error(t *testing.T) {
+	if _, err := Scan(context.Background(), &fakeNode{fail: 7}, 1, 100, WithConcurrency(3)); err == nil || err.Error() != "node error" {
+		t.Errorf("Scan want node error, got %v", err)
+	}
+}
+
+func TestReport_AddEvent_invalidAmount(t *testing.T) {
+	err := NewReport(1, 1).AddEvent(&api.Event{Type: api.EventTypeSlash, Value: map[string]string{"amount": "-"}})
+	if err == nil {
+		t.Error("AddEvent want error")
+	}
+}
+
+func TestReport_AddEvent_replacedDecoder(t *testing.T) {
+	api.RegisterEventDecoder(api.EventTypeSlash, func(value map[string]string) (interface{}, error) {
+		return value["amount"], nil
+	})
+	t.Cleanup(func() {
+		api.RegisterEventDecoder(api.EventTypeSlash, api.JSONEventDecoder(func() interface{} { return &api.SlashEventValue{} }))
+	})
+	err := NewReport(1, 1).AddEvent(&api.Event{Type: api.EventTypeSlash, Value: map[string]string{"amount": "1"}})
+	if err == nil {
+		t.Error("AddEvent want error")
+	}
+}
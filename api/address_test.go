@@ -1,5 +1,3 @@
-// +build integration
-
 package api
 
 import (
@@ -9,6 +7,7 @@ import (
 func TestApi_Address(t *testing.T) {
 	response, err := testApi.Address("Mxeeee1973381ab793719fff497b9a516719fcd5a2")
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 	t.Logf("%+v", response)
@@ -1,7 +1,37 @@
-// +build integration
-
 package api
 
-import "os"
+import (
+	"errors"
+	"flag"
+	"github.com/go-resty/resty/v2"
+	"github.com/nikolaev-dev/sdk/api/fixture"
+	"os"
+	"testing"
+)
+
+// Tests of endpoints replay responses of a test net node from testdata/fixtures.
+// Run "TEST_NET_CHAIN_API_HOST_URL=... go test ./api -record" to record them,
+// tests without recorded responses are skipped.
+var record = flag.Bool("record", false, "request TEST_NET_CHAIN_API_HOST_URL node and record fixtures of api tests")
+
+const fixturesDir = "testdata/fixtures"
+
+var testApi *Api
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if *record {
+		testApi = NewApiWithClient(os.Getenv("TEST_NET_CHAIN_API_HOST_URL"), resty.New().SetTransport(fixture.NewRecorder(fixturesDir, nil)))
+	} else {
+		testApi = NewApiWithClient("http://replay", resty.New().SetTransport(fixture.NewReplayer(fixturesDir)))
+	}
+	os.Exit(m.Run())
+}
 
-var testApi = NewApi(os.Getenv("TEST_NET_CHAIN_API_HOST_URL"))
+// Skips test, which requests response not recorded to fixtures yet.
+func skipNotRecorded(t *testing.T, err error) {
+	t.Helper()
+	if errors.Is(err, fixture.ErrNotRecorded) {
+		t.Skip(err)
+	}
+}
@@ -0,0 +1,533 @@
+package apitest
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/nikolaev-dev/sdk/api"
+	"github.com/nikolaev-dev/sdk/transaction"
+	"github.com/nikolaev-dev/sdk/wallet"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrCoinNotFound = errors.New("coin not found")
+	ErrSameCoins    = errors.New("\"From\" coin equals to \"to\" coin")
+	ErrInvalidValue = errors.New("invalid value")
+)
+
+// Codes of transaction check errors, the same as the node uses.
+const (
+	CodeWrongNonce        = 101
+	CodeCoinNotExists     = 102
+	CodeDecodeError       = 106
+	CodeInsufficientFunds = 107
+	CodeTooLowGasPrice    = 114
+	CodeWrongChainID      = 115
+)
+
+// Time of the genesis block, every next block is 5 seconds later.
+var GenesisTime = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// In-memory state of a fake node: balances, nonces, blocks with transactions, events, coins, candidates and validators.
+// Every applied transaction is committed in its own block. It is safe for concurrent use.
+// State is not versioned, requests at past heights see the current state except blocks and events.
+type Ledger struct {
+	mu sync.Mutex
+
+	chainID     transaction.ChainID
+	baseCoin    string
+	minGasPrice int
+	maxGas      int
+
+	blocks       []*api.BlockResult
+	transactions map[string]*api.TransactionResult
+	events       map[int][]api.Event
+	balances     map[string]map[string]*big.Int
+	counts       map[string]uint64
+	coins        map[string]*api.CoinInfoResult
+	candidates   []*api.CandidateResult
+	validators   []*api.ValidatorResult
+
+	hooks []func(*api.BlockResult)
+}
+
+// Create ledger with genesis block at height 1, test net chain ID and MNT base coin.
+func NewLedger() *Ledger {
+	l := &Ledger{
+		chainID:      transaction.TestNetChainID,
+		baseCoin:     "MNT",
+		minGasPrice:  1,
+		maxGas:       100000,
+		transactions: make(map[string]*api.TransactionResult),
+		events:       make(map[int][]api.Event),
+		balances:     make(map[string]map[string]*big.Int),
+		counts:       make(map[string]uint64),
+		coins:        make(map[string]*api.CoinInfoResult),
+	}
+	l.commit(nil)
+	return l
+}
+
+// Set chain ID of accepted transactions.
+func (l *Ledger) SetChainID(chainID transaction.ChainID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.chainID = chainID
+}
+
+// Returns symbol of base coin.
+func (l *Ledger) BaseCoin() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.baseCoin
+}
+
+// Set minimal gas price of accepted transactions.
+func (l *Ledger) SetMinGasPrice(price int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.minGasPrice = price
+}
+
+// Set balance of address in coin.
+func (l *Ledger) SetBalance(address, coin string, amount *big.Int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.balance(address)[coin] = new(big.Int).Set(amount)
+}
+
+// Returns balance of address in coin.
+func (l *Ledger) Balance(address, coin string) *big.Int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return new(big.Int).Set(l.balanceOf(address, coin))
+}
+
+// Returns number of transactions sent from address.
+func (l *Ledger) TransactionCount(address string) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.counts[strings.ToLower(address)]
+}
+
+// Add custom coin, it can be used in transactions.
+func (l *Ledger) AddCoin(coin api.CoinInfoResult) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.coins[coin.Symbol] = &coin
+}
+
+// Add candidate, its public key is used for validator of blocks if it is added to validators.
+func (l *Ledger) AddCandidate(candidate api.CandidateResult) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.candidates = append(l.candidates, &candidate)
+}
+
+// Add validator.
+func (l *Ledger) AddValidator(validator api.ValidatorResult) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.validators = append(l.validators, &validator)
+}
+
+// Add events to block at height.
+func (l *Ledger) AddEvents(height int, events ...api.Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events[height] = append(l.events[height], events...)
+}
+
+// Returns height of the latest block.
+func (l *Ledger) Height() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.blocks)
+}
+
+// Commit empty block and return it.
+func (l *Ledger) Commit() *api.BlockResult {
+	l.mu.Lock()
+	block := l.commit(nil)
+	hooks := l.hooks
+	l.mu.Unlock()
+	notify(hooks, block)
+	return block
+}
+
+// Call f with every block committed after the call, e.g. to publish events of subscriptions.
+// It is called synchronously by Commit and Apply.
+func (l *Ledger) OnCommit(f func(block *api.BlockResult)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks[:len(l.hooks):len(l.hooks)], f)
+}
+
+func notify(hooks []func(*api.BlockResult), block *api.BlockResult) {
+	for _, f := range hooks {
+		b := *block
+		b.Transactions = append([]api.TransactionResult(nil), block.Transactions...)
+		f(&b)
+	}
+}
+
+func (l *Ledger) commit(transactions []api.TransactionResult) *api.BlockResult {
+	height := len(l.blocks) + 1
+	var seed [8]byte
+	binary.BigEndian.PutUint64(seed[:], uint64(height))
+	hash := sha256.Sum256(seed[:])
+	block := &api.BlockResult{
+		Hash:         strings.ToUpper(hex.EncodeToString(hash[:])),
+		Height:       strconv.Itoa(height),
+		Time:         GenesisTime.Add(time.Duration(height-1) * 5 * time.Second),
+		NumTxs:       strconv.Itoa(len(transactions)),
+		Transactions: transactions,
+		BlockReward:  "333000000000000000000",
+		Size:         strconv.Itoa(100 + len(transactions)*200),
+	}
+	if len(l.blocks) != 0 {
+		total, _ := strconv.Atoi(l.blocks[len(l.blocks)-1].TotalTxs)
+		block.TotalTxs = strconv.Itoa(total + len(transactions))
+	} else {
+		block.TotalTxs = block.NumTxs
+	}
+	for _, validator := range l.validators {
+		block.Validators = append(block.Validators, struct {
+			PubKey string `json:"pub_key"`
+			Signed bool   `json:"signed"`
+		}{PubKey: validator.PubKey, Signed: true})
+	}
+	if len(l.validators) != 0 {
+		block.Proposer = l.validators[(height-1)%len(l.validators)].PubKey
+	}
+	for i := range block.Transactions {
+		block.Transactions[i].Height = block.Height
+		block.Transactions[i].Index = i
+		tx := block.Transactions[i]
+		l.transactions[hashKey(tx.Hash)] = &tx
+	}
+	l.blocks = append(l.blocks, block)
+	return block
+}
+
+func (l *Ledger) balance(address string) map[string]*big.Int {
+	address = strings.ToLower(address)
+	balance, ok := l.balances[address]
+	if !ok {
+		balance = make(map[string]*big.Int)
+		l.balances[address] = balance
+	}
+	return balance
+}
+
+func (l *Ledger) balanceOf(address, coin string) *big.Int {
+	if amount, ok := l.balances[strings.ToLower(address)][coin]; ok {
+		return amount
+	}
+	return new(big.Int)
+}
+
+func (l *Ledger) coinExists(symbol string) bool {
+	_, ok := l.coins[symbol]
+	return symbol == l.baseCoin || ok
+}
+
+// Error of transaction check.
+func txError(code int, format string, args ...interface{}) *api.TxError {
+	err := &api.TxError{Code: 412, Message: "Check tx error"}
+	err.TxResult.Code = code
+	err.TxResult.Log = fmt.Sprintf(format, args...)
+	return err
+}
+
+// Validate signed transaction and apply it in a new block. Check errors are returned as *api.TxError with codes of the node.
+func (l *Ledger) Apply(rawTx string) (*api.TransactionResult, error) {
+	tx, err := transaction.Decode(rawTx)
+	if err != nil {
+		return nil, txError(CodeDecodeError, "decode error: %s", err)
+	}
+	sender, err := senderAddress(tx)
+	if err != nil {
+		return nil, txError(CodeDecodeError, "decode error: %s", err)
+	}
+	hash, err := tx.Hash()
+	if err != nil {
+		return nil, txError(CodeDecodeError, "decode error: %s", err)
+	}
+	data, ok := tx.Data().(*transaction.SendData)
+	if !ok || data.Value == nil {
+		return nil, txError(CodeDecodeError, "unsupported transaction data %T", tx.Data())
+	}
+
+	l.mu.Lock()
+	block, err := l.apply(tx, sender, hash, data, rawTx)
+	hooks := l.hooks
+	l.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	notify(hooks, block)
+	applied := block.Transactions[0]
+	return &applied, nil
+}
+
+func (l *Ledger) apply(tx transaction.SignedTransaction, sender, hash string, data *transaction.SendData, rawTx string) (*api.BlockResult, error) {
+	t := tx.GetTransaction()
+	if t.ChainID != l.chainID {
+		return nil, txError(CodeWrongChainID, "wrong chain id")
+	}
+	if int(t.GasPrice) < l.minGasPrice {
+		return nil, txError(CodeTooLowGasPrice, "gas price of tx is too low to be included in mempool. Expected %d", l.minGasPrice)
+	}
+	gasCoin, coin := t.GasCoin.String(), data.Coin.String()
+	if !l.coinExists(gasCoin) {
+		return nil, txError(CodeCoinNotExists, "coin %s not exists", gasCoin)
+	}
+	if !l.coinExists(coin) {
+		return nil, txError(CodeCoinNotExists, "coin %s not exists", coin)
+	}
+	key := strings.ToLower(sender)
+	if want := l.counts[key] + 1; t.Nonce != want {
+		return nil, txError(CodeWrongNonce, "unexpected nonce. Expected: %d, got %d.", want, t.Nonce)
+	}
+
+	commission := new(big.Int).Mul(tx.Fee(), big.NewInt(int64(t.GasPrice)))
+	required := map[string]*big.Int{gasCoin: new(big.Int).Set(commission)}
+	if _, ok := required[coin]; !ok {
+		required[coin] = new(big.Int)
+	}
+	required[coin].Add(required[coin], data.Value)
+	for symbol, amount := range required {
+		if l.balanceOf(sender, symbol).Cmp(amount) < 0 {
+			return nil, txError(CodeInsufficientFunds, "insufficient funds for sender account: %s. Wanted %s %s", sender, amount, symbol)
+		}
+	}
+
+	senderBalance := l.balance(sender)
+	for symbol, amount := range required {
+		senderBalance[symbol] = new(big.Int).Sub(l.balanceOf(sender, symbol), amount)
+	}
+	to := wallet.BytesToAddress(data.To)
+	recipientBalance := l.balance(to)
+	recipientBalance[coin] = new(big.Int).Add(l.balanceOf(to, coin), data.Value)
+	l.counts[key]++
+
+	result := api.TransactionResult{
+		Hash:        hash,
+		RawTx:       strings.TrimPrefix(rawTx, "0x"),
+		From:        sender,
+		Nonce:       strconv.FormatUint(t.Nonce, 10),
+		Gas:         new(big.Int).Div(tx.Fee(), big.NewInt(1000000000000000)).String(),
+		GasPrice:    int(t.GasPrice),
+		GasCoin:     gasCoin,
+		Type:        int(t.Type),
+		Data:        map[string]interface{}{"coin": coin, "to": to, "value": data.Value.String()},
+		Payload:     t.Payload,
+		ServiceData: t.ServiceData,
+	}
+	result.Tags.TxType = hex.EncodeToString([]byte{byte(t.Type)})
+	result.Tags.TxFrom = strings.TrimPrefix(key, "mx")
+	result.Tags.TxTo = strings.TrimPrefix(strings.ToLower(to), "mx")
+	result.Tags.TxCoin = coin
+	return l.commit([]api.TransactionResult{result}), nil
+}
+
+// Returns sender of transaction, its signatures are checked to be valid and canonical like the node does.
+// Members of multisig sender are not known to the ledger and are not checked.
+func senderAddress(tx transaction.SignedTransaction) (string, error) {
+	if len(tx.SignatureData()) == 0 {
+		return "", errors.New("transaction is not signed")
+	}
+	sender, err := tx.SenderAddress()
+	if err != nil {
+		return "", err
+	}
+	if err := transaction.Verify(tx, sender, tx.GetTransaction().ChainID); err != nil {
+		return "", err
+	}
+	return sender, nil
+}
+
+// Returns block at height.
+func (l *Ledger) Block(height int) (*api.BlockResult, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if height < 1 || height > len(l.blocks) {
+		return nil, false
+	}
+	block := *l.blocks[height-1]
+	return &block, true
+}
+
+// Returns events of block at height.
+func (l *Ledger) Events(height int) []api.Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]api.Event{}, l.events[height]...)
+}
+
+// Transactions are indexed by lowercase hex hash without "Mt" prefix.
+func hashKey(hash string) string {
+	hash = strings.ToLower(hash)
+	hash = strings.TrimPrefix(hash, "mt")
+	return strings.TrimPrefix(hash, "0x")
+}
+
+// Returns transaction by hash with or without "Mt" prefix.
+func (l *Ledger) Transaction(hash string) (*api.TransactionResult, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	tx, ok := l.transactions[hashKey(hash)]
+	if !ok {
+		return nil, false
+	}
+	c := *tx
+	return &c, true
+}
+
+// Returns transactions matching Tendermint query in order of heights.
+// Conditions on "tx.height", "tx.hash" and "tags.tx.*" joined with AND are supported.
+func (l *Ledger) Transactions(query string) ([]*api.TransactionResult, error) {
+	q, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var result []*api.TransactionResult
+	for _, block := range l.blocks {
+		for i := range block.Transactions {
+			tx := block.Transactions[i]
+			if q.Match(TransactionKeys(&tx)) {
+				result = append(result, &tx)
+			}
+		}
+	}
+	return result, nil
+}
+
+// Returns keys of transaction indexed by the node: "tx.hash", "tx.height" and tags with prefix "tags.".
+func TransactionKeys(tx *api.TransactionResult) map[string][]string {
+	keys := map[string][]string{
+		"tx.hash":   {strings.ToUpper(strings.TrimPrefix(tx.Hash, "Mt"))},
+		"tx.height": {tx.Height},
+	}
+	b, _ := json.Marshal(tx.Tags)
+	var tags map[string]string
+	_ = json.Unmarshal(b, &tags)
+	for key, value := range tags {
+		keys["tags."+key] = []string{value}
+	}
+	return keys
+}
+
+// Returns address state.
+func (l *Ledger) Address(address string) *api.AddressResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	result := &api.AddressResult{
+		Balance:          map[string]string{l.baseCoin: "0"},
+		TransactionCount: strconv.FormatUint(l.counts[strings.ToLower(address)], 10),
+	}
+	for coin, amount := range l.balances[strings.ToLower(address)] {
+		result.Balance[coin] = amount.String()
+	}
+	return result
+}
+
+// Returns coin info by symbol.
+func (l *Ledger) Coin(symbol string) (*api.CoinInfoResult, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	coin, ok := l.coins[symbol]
+	if !ok {
+		return nil, false
+	}
+	c := *coin
+	return &c, true
+}
+
+// Returns candidate by public key.
+func (l *Ledger) Candidate(pubKey string) (*api.CandidateResult, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, candidate := range l.candidates {
+		if candidate.PubKey == pubKey {
+			c := *candidate
+			return &c, true
+		}
+	}
+	return nil, false
+}
+
+// Returns candidates, without stakes if includeStakes is false.
+func (l *Ledger) Candidates(includeStakes bool) []*api.CandidateResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	result := make([]*api.CandidateResult, 0, len(l.candidates))
+	for _, candidate := range l.candidates {
+		c := *candidate
+		if !includeStakes {
+			c.Stakes = nil
+		}
+		result = append(result, &c)
+	}
+	return result
+}
+
+// Returns validators sorted by public key.
+func (l *Ledger) Validators() []*api.ValidatorResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	result := make([]*api.ValidatorResult, 0, len(l.validators))
+	for _, validator := range l.validators {
+		v := *validator
+		result = append(result, &v)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].PubKey < result[j].PubKey })
+	return result
+}
+
+// Returns estimate of coins exchange and its commission. Exchange rate of all coins is 1:1,
+// commission is the fee of convert transaction in base coin.
+func (l *Ledger) Estimate(coinToSell, coinToBuy, value string) (*big.Int, *big.Int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.coinExists(coinToSell) || !l.coinExists(coinToBuy) {
+		return nil, nil, ErrCoinNotFound
+	}
+	if coinToSell == coinToBuy {
+		return nil, nil, ErrSameCoins
+	}
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, nil, ErrInvalidValue
+	}
+	return amount, big.NewInt(100000000000000000), nil
+}
+
+// Returns commission of signed transaction.
+func (l *Ledger) EstimateTxCommission(rawTx string) (*big.Int, error) {
+	tx, err := transaction.Decode(rawTx)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Mul(tx.Fee(), big.NewInt(int64(tx.GetTransaction().GasPrice))), nil
+}
+
+// Returns minimal gas price and maximal gas of block.
+func (l *Ledger) Gas() (minGasPrice, maxGas int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.minGasPrice, l.maxGas
+}
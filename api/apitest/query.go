@@ -0,0 +1,77 @@
+package apitest
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	queryAnd       = regexp.MustCompile(`\s+AND\s+`)
+	queryCondition = regexp.MustCompile(`^\s*([a-zA-Z_][a-zA-Z0-9_.]*)\s*(<=|>=|=|<|>)\s*('[^']*'|\d+)\s*$`)
+)
+
+type condition struct {
+	key, operator, value string
+}
+
+// Parsed Tendermint query, conditions joined with AND with operators =, <, <=, >, >= are supported.
+type Query struct {
+	conditions []condition
+}
+
+// Parse Tendermint query, e.g. "tm.event='Tx' AND tx.height>=10".
+func ParseQuery(query string) (*Query, error) {
+	q := &Query{}
+	for _, part := range queryAnd.Split(strings.TrimSpace(query), -1) {
+		match := queryCondition.FindStringSubmatch(part)
+		if match == nil {
+			return nil, fmt.Errorf("failed to parse query: %q", query)
+		}
+		q.conditions = append(q.conditions, condition{key: match[1], operator: match[2], value: strings.Trim(match[3], "'")})
+	}
+	return q, nil
+}
+
+// Reports whether every condition matches a value of its key. Strings are compared case-insensitively.
+func (q *Query) Match(values map[string][]string) bool {
+	for _, c := range q.conditions {
+		matched := false
+		for _, value := range values[c.key] {
+			if c.match(value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func (c condition) match(actual string) bool {
+	if c.operator == "=" {
+		return strings.EqualFold(actual, c.value)
+	}
+	a, err := strconv.Atoi(actual)
+	if err != nil {
+		return false
+	}
+	v, err := strconv.Atoi(c.value)
+	if err != nil {
+		return false
+	}
+	switch c.operator {
+	case "<=":
+		return a <= v
+	case ">=":
+		return a >= v
+	case "<":
+		return a < v
+	case ">":
+		return a > v
+	}
+	return false
+}
@@ -0,0 +1,317 @@
+// Package apitest provides in-process fake Minter node serving the API endpoints used by api.Api,
+// backed by in-memory Ledger, for offline tests of code using the SDK.
+//
+//	server := apitest.NewServer(nil)
+//	defer server.Close()
+//	server.Ledger.SetBalance(address, "MNT", big.NewInt(1e18))
+//	client := server.Api()
+package apitest
+
+import (
+	"encoding/json"
+	"github.com/nikolaev-dev/sdk/api"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fault injected into responses of the server.
+type Fault struct {
+	// Path of endpoint, e.g. "/block", empty path matches all endpoints.
+	Path string
+	// Delay of response.
+	Latency time.Duration
+	// HTTP status of response, api.Api returns *api.ResponseError for status >= 400.
+	Status int
+	// JSON-RPC error of response with status 200 if Status is not set.
+	Error *api.Error
+	// Number of affected requests, 0 means all requests.
+	Times int
+}
+
+// Fake node HTTP server.
+type Server struct {
+	*httptest.Server
+	Ledger *Ledger
+
+	mu     sync.Mutex
+	faults []*Fault
+}
+
+// Start fake node server with ledger, nil ledger is replaced with NewLedger().
+func NewServer(ledger *Ledger) *Server {
+	if ledger == nil {
+		ledger = NewLedger()
+	}
+	s := &Server{Ledger: ledger}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Create client of the server.
+func (s *Server) Api() *api.Api {
+	return api.NewApi(s.URL)
+}
+
+// Inject fault into responses. Faults are checked in order of injection, the first matching fault is applied.
+func (s *Server) Inject(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// Remove all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+func (s *Server) fault(path string) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, fault := range s.faults {
+		if fault.Path != "" && fault.Path != path {
+			continue
+		}
+		f := *fault
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return &f
+	}
+	return nil
+}
+
+type response struct {
+	Jsonrpc string      `json:"jsonrpc"`
+	ID      string      `json:"id"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   interface{} `json:"error,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeResult(w http.ResponseWriter, result interface{}) {
+	writeJSON(w, http.StatusOK, &response{Jsonrpc: "2.0", Result: result})
+}
+
+func writeError(w http.ResponseWriter, err interface{}) {
+	writeJSON(w, http.StatusOK, &response{Jsonrpc: "2.0", Error: err})
+}
+
+func notFound(message string) *api.Error {
+	return &api.Error{Code: 404, Message: message}
+}
+
+func invalidParams(message string) *api.Error {
+	return &api.Error{Code: -32602, Message: "Invalid params", Data: message}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if fault := s.fault(r.URL.Path); fault != nil {
+		if fault.Latency > 0 {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(fault.Latency):
+			}
+		}
+		if fault.Status != 0 {
+			writeJSON(w, fault.Status, &response{Jsonrpc: "2.0", Error: &api.Error{Code: fault.Status, Message: http.StatusText(fault.Status)}})
+			return
+		}
+		if fault.Error != nil {
+			writeError(w, fault.Error)
+			return
+		}
+	}
+
+	query := r.URL.Query()
+	l := s.Ledger
+	switch r.URL.Path {
+	case "/status":
+		block, _ := l.Block(l.Height())
+		result := &api.StatusResult{
+			Version:           "apitest",
+			LatestBlockHash:   block.Hash,
+			LatestAppHash:     block.Hash,
+			LatestBlockHeight: block.Height,
+			LatestBlockTime:   block.Time,
+			StateHistory:      "on",
+		}
+		result.TmStatus.SyncInfo.LatestBlockHash = block.Hash
+		result.TmStatus.SyncInfo.LatestBlockHeight = block.Height
+		result.TmStatus.SyncInfo.LatestBlockTime = block.Time
+		writeResult(w, result)
+	case "/address":
+		writeResult(w, l.Address(query.Get("address")))
+	case "/addresses":
+		var results []*api.AddressesResult
+		for _, address := range strings.Split(strings.Trim(query.Get("addresses"), "[]"), ",") {
+			address = strings.Trim(strings.TrimSpace(address), `"`)
+			if address == "" {
+				continue
+			}
+			state := l.Address(address)
+			results = append(results, &api.AddressesResult{Address: address, Balance: state.Balance, TransactionCount: state.TransactionCount})
+		}
+		writeResult(w, results)
+	case "/block":
+		height, _ := strconv.Atoi(query.Get("height"))
+		block, ok := l.Block(height)
+		if !ok {
+			writeError(w, notFound("Block not found"))
+			return
+		}
+		writeResult(w, block)
+	case "/events":
+		height, _ := strconv.Atoi(query.Get("height"))
+		if height == 0 {
+			height = l.Height()
+		}
+		writeResult(w, &api.EventsResult{Events: l.Events(height)})
+	case "/candidate":
+		candidate, ok := l.Candidate(query.Get("pub_key"))
+		if !ok {
+			writeError(w, notFound("Candidate not found"))
+			return
+		}
+		writeResult(w, candidate)
+	case "/candidates":
+		writeResult(w, l.Candidates(query.Get("include_stakes") == "true"))
+	case "/coin_info":
+		coin, ok := l.Coin(query.Get("symbol"))
+		if !ok {
+			writeError(w, notFound("Coin not found"))
+			return
+		}
+		writeResult(w, coin)
+	case "/estimate_coin_buy":
+		s.estimate(w, query.Get("coin_to_sell"), query.Get("coin_to_buy"), query.Get("value_to_buy"), func(value, commission string) interface{} {
+			return &api.EstimateCoinBuyResult{WillPay: value, Commission: commission}
+		})
+	case "/estimate_coin_sell":
+		s.estimate(w, query.Get("coin_to_sell"), query.Get("coin_to_buy"), query.Get("value_to_sell"), func(value, commission string) interface{} {
+			return &api.EstimateCoinSellResult{WillGet: value, Commission: commission}
+		})
+	case "/estimate_coin_sell_all":
+		s.estimate(w, query.Get("coin_to_sell"), query.Get("coin_to_buy"), query.Get("value_to_sell"), func(value, commission string) interface{} {
+			return &api.EstimateCoinSellAllResult{WillGet: value}
+		})
+	case "/estimate_tx_commission":
+		commission, err := l.EstimateTxCommission(query.Get("tx"))
+		if err != nil {
+			writeError(w, invalidParams(err.Error()))
+			return
+		}
+		writeResult(w, &api.EstimateTxCommissionResult{Commission: commission.String()})
+	case "/max_gas":
+		_, maxGas := l.Gas()
+		writeResult(w, strconv.Itoa(maxGas))
+	case "/min_gas_price":
+		minGasPrice, _ := l.Gas()
+		writeResult(w, strconv.Itoa(minGasPrice))
+	case "/missed_blocks":
+		if _, ok := l.Candidate(query.Get("pub_key")); !ok {
+			writeError(w, notFound("Validator not found"))
+			return
+		}
+		writeResult(w, &api.MissedBlocksResult{MissedBlocks: strings.Repeat("x", 24), MissedBlocksCount: "0"})
+	case "/send_transaction":
+		tx, err := l.Apply(query.Get("tx"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResult(w, &api.SendTransactionResult{Code: 0, Hash: strings.ToUpper(strings.TrimPrefix(tx.Hash, "Mt"))})
+	case "/transaction":
+		tx, ok := l.Transaction(query.Get("hash"))
+		if !ok {
+			writeError(w, notFound("Transaction not found"))
+			return
+		}
+		writeResult(w, tx)
+	case "/transactions":
+		transactions, err := l.Transactions(query.Get("query"))
+		if err != nil {
+			writeError(w, invalidParams(err.Error()))
+			return
+		}
+		from, to, err := Page(query.Get("page"), query.Get("perPage"), len(transactions))
+		if err != nil {
+			writeError(w, &api.Error{Code: -32603, Message: "Internal error", Data: err.Error()})
+			return
+		}
+		writeResult(w, transactions[from:to])
+	case "/unconfirmed_txs":
+		writeResult(w, &api.UnconfirmedTxsResult{NTxs: "0", Total: "0", TotalBytes: "0", Txs: []string{}})
+	case "/validators":
+		validators := l.Validators()
+		from, to, err := Page(query.Get("page"), query.Get("perPage"), len(validators))
+		if err != nil {
+			writeError(w, &api.Error{Code: -32603, Message: "Internal error", Data: err.Error()})
+			return
+		}
+		writeResult(w, validators[from:to])
+	default:
+		writeJSON(w, http.StatusNotFound, &response{Jsonrpc: "2.0", Error: &api.Error{Code: -32601, Message: "Method not found"}})
+	}
+}
+
+func (s *Server) estimate(w http.ResponseWriter, coinToSell, coinToBuy, value string, result func(value, commission string) interface{}) {
+	amount, commission, err := s.Ledger.Estimate(coinToSell, coinToBuy, value)
+	if err == ErrCoinNotFound {
+		writeError(w, notFound("Coin not found"))
+		return
+	}
+	if err != nil {
+		writeError(w, invalidParams(err.Error()))
+		return
+	}
+	writeResult(w, result(amount.String(), commission.String()))
+}
+
+// Returns bounds of the page of count items like Tendermint does: page 0 is the first one, perPage out of 1..100
+// is replaced with 30, page out of range is error "page should be within [1, N] range, given P".
+func Page(pageParam, perPageParam string, count int) (int, int, error) {
+	page, _ := strconv.Atoi(pageParam)
+	perPage, _ := strconv.Atoi(perPageParam)
+	if page == 0 {
+		page = 1
+	}
+	if perPage <= 0 || perPage > api.DefaultPerPage {
+		perPage = 30
+	}
+	pages := (count + perPage - 1) / perPage
+	if pages == 0 {
+		pages = 1
+	}
+	if page < 1 || page > pages {
+		return 0, 0, &pageError{pages: pages, page: page}
+	}
+	from := (page - 1) * perPage
+	to := from + perPage
+	if to > count {
+		to = count
+	}
+	return from, to, nil
+}
+
+type pageError struct {
+	pages, page int
+}
+
+func (e *pageError) Error() string {
+	return "page should be within [1, " + strconv.Itoa(e.pages) + "] range, given " + strconv.Itoa(e.page)
+}
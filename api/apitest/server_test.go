@@ -0,0 +1,234 @@
+package apitest
+
+import (
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/go-resty/resty/v2"
+	"github.com/nikolaev-dev/sdk/api"
+	"github.com/nikolaev-dev/sdk/transaction"
+	"github.com/nikolaev-dev/sdk/wallet"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const (
+	privateKey = "07bc17abdcee8b971bb8723e36fe9d2523306d5ab2d683631693238e0f9df142"
+	recipient  = "Mx1b685a7c1e78726c48f619c497a07ed75fe00483"
+)
+
+func senderOf(t *testing.T) string {
+	publicKey, err := wallet.PublicKeyByPrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, err := wallet.AddressByPublicKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return address
+}
+
+func signedSend(t *testing.T, nonce uint64, value *big.Int) transaction.SignedTransaction {
+	data, err := transaction.NewSendData().SetCoin("MNT").SetValue(value).SetTo(recipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := transaction.NewBuilder(transaction.TestNetChainID).NewTransaction(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := tx.SetNonce(nonce).SetGasPrice(1).SetGasCoin("MNT").Sign(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func txErrorCode(t *testing.T, err error) int {
+	txErr, ok := err.(*api.TxError)
+	if !ok {
+		t.Fatalf("err want *api.TxError, got %T %v", err, err)
+	}
+	return txErr.TxResult.Code
+}
+
+func TestServer_SendTransaction(t *testing.T) {
+	server := NewServer(nil)
+	defer server.Close()
+	client := server.Api()
+	sender := senderOf(t)
+	server.Ledger.SetBalance(sender, "MNT", big.NewInt(0).Exp(big.NewInt(10), big.NewInt(19), nil))
+
+	nonce, err := client.Nonce(sender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := big.NewInt(1000)
+	signed := signedSend(t, nonce, value)
+	estimate, err := client.EstimateTxCommission(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate.Commission != signed.Fee().String() {
+		t.Errorf("commission want %s, got %s", signed.Fee(), estimate.Commission)
+	}
+	result, err := client.SendTransaction(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := client.Transaction("Mt" + result.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.From != sender || tx.Height != "2" {
+		t.Errorf("unexpected transaction %+v", tx)
+	}
+	block, err := client.Block(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(block.Transactions) != 1 || block.Transactions[0].Hash != tx.Hash {
+		t.Errorf("unexpected block transactions %+v", block.Transactions)
+	}
+
+	balance, err := client.Balance(recipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance["MNT"] != value.String() {
+		t.Errorf("recipient balance want %s, got %s", value, balance["MNT"])
+	}
+	commission := new(big.Int).Mul(signed.Fee(), big.NewInt(1))
+	want := new(big.Int).Exp(big.NewInt(10), big.NewInt(19), nil)
+	want.Sub(want, value).Sub(want, commission)
+	if got := server.Ledger.Balance(sender, "MNT"); got.Cmp(want) != 0 {
+		t.Errorf("sender balance want %s, got %s", want, got)
+	}
+	if nonce, err := client.Nonce(sender); err != nil || nonce != 2 {
+		t.Errorf("nonce want 2, got %d, %v", nonce, err)
+	}
+
+	transactions, err := client.Transactions(api.NewTransactionsQuery().From(sender).String(), 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transactions) != 1 || transactions[0].Hash != tx.Hash {
+		t.Errorf("unexpected transactions %+v", transactions)
+	}
+	transactions, err = client.Transactions(api.NewTransactionsQuery().From(recipient).String(), 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transactions) != 0 {
+		t.Errorf("unexpected transactions %+v", transactions)
+	}
+}
+
+func TestServer_SendTransaction_checkErrors(t *testing.T) {
+	server := NewServer(nil)
+	defer server.Close()
+	client := server.Api()
+	server.Ledger.SetBalance(senderOf(t), "MNT", big.NewInt(0).Exp(big.NewInt(10), big.NewInt(18), nil))
+
+	_, err := client.SendTransaction(signedSend(t, 2, big.NewInt(1)))
+	if code := txErrorCode(t, err); code != CodeWrongNonce {
+		t.Errorf("code want %d, got %d", CodeWrongNonce, code)
+	}
+
+	_, err = client.SendTransaction(signedSend(t, 1, big.NewInt(0).Exp(big.NewInt(10), big.NewInt(18), nil)))
+	if code := txErrorCode(t, err); code != CodeInsufficientFunds {
+		t.Errorf("code want %d, got %d", CodeInsufficientFunds, code)
+	}
+
+	server.Ledger.SetMinGasPrice(2)
+	_, err = client.SendTransaction(signedSend(t, 1, big.NewInt(1)))
+	if code := txErrorCode(t, err); code != CodeTooLowGasPrice {
+		t.Errorf("code want %d, got %d", CodeTooLowGasPrice, code)
+	}
+
+	_, err = client.SendRawTransaction("0xf8")
+	if code := txErrorCode(t, err); code != CodeDecodeError {
+		t.Errorf("code want %d, got %d", CodeDecodeError, code)
+	}
+
+	// the same signature with S = N - S recovers the same sender, but is rejected by the node
+	malleable := signedSend(t, 1, big.NewInt(1)).GetTransaction()
+	signature := new(transaction.Signature)
+	if err := rlp.DecodeBytes(malleable.SignatureData, signature); err != nil {
+		t.Fatal(err)
+	}
+	signature.V = big.NewInt(55 - signature.V.Int64())
+	signature.S = new(big.Int).Sub(crypto.S256().Params().N, signature.S)
+	if malleable.SignatureData, err = rlp.EncodeToBytes(signature); err != nil {
+		t.Fatal(err)
+	}
+	rawTx, err := malleable.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.SendRawTransaction(rawTx)
+	if code := txErrorCode(t, err); code != CodeDecodeError {
+		t.Errorf("code of malleable signature want %d, got %d", CodeDecodeError, code)
+	}
+
+	if height := server.Ledger.Height(); height != 1 {
+		t.Errorf("rejected transactions should not be committed, height %d", height)
+	}
+}
+
+func TestServer_Inject(t *testing.T) {
+	server := NewServer(nil)
+	defer server.Close()
+	client := server.Api()
+
+	server.Inject(Fault{Path: "/status", Status: 503, Times: 1})
+	if _, err := client.Status(); err == nil {
+		t.Fatal("want error")
+	} else if _, ok := err.(*api.ResponseError); !ok {
+		t.Fatalf("err want *api.ResponseError, got %T", err)
+	}
+	if _, err := client.Status(); err != nil {
+		t.Fatalf("fault should be applied once, got %v", err)
+	}
+
+	server.Inject(Fault{Path: "/block", Error: &api.Error{Code: 404, Message: "Block not found"}})
+	for i := 0; i < 2; i++ {
+		if _, err := client.Block(1); err == nil {
+			t.Fatal("want error")
+		} else if e, ok := err.(*api.Error); !ok || e.Code != 404 {
+			t.Fatalf("err want *api.Error, got %T %v", err, err)
+		}
+	}
+	server.ClearFaults()
+	if _, err := client.Block(1); err != nil {
+		t.Fatal(err)
+	}
+
+	server.Inject(Fault{Latency: time.Second})
+	client = api.NewApiWithClient(server.URL, resty.New().SetTimeout(50*time.Millisecond))
+	if _, err := client.Status(); err == nil {
+		t.Fatal("want timeout error")
+	}
+}
+
+func TestServer_ValidatorsPages(t *testing.T) {
+	server := NewServer(nil)
+	defer server.Close()
+	for i := 0; i < 2*api.DefaultPerPage+1; i++ {
+		server.Ledger.AddValidator(api.ValidatorResult{PubKey: "Mp" + strconv.Itoa(1000+i), VotingPower: "1"})
+	}
+
+	validators, err := server.Api().Validators()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(validators) != 2*api.DefaultPerPage+1 {
+		t.Errorf("validators want %d, got %d", 2*api.DefaultPerPage+1, len(validators))
+	}
+	if _, err := server.Api().ValidatorsPage(0, 4, api.DefaultPerPage); err == nil {
+		t.Error("page out of range want error")
+	}
+}
@@ -1,5 +1,3 @@
-// +build integration
-
 package api
 
 import (
@@ -11,6 +9,7 @@ import (
 func TestApi_Block(t *testing.T) {
 	response, err := testApi.Block(19)
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 	for _, v := range response.Transactions {
@@ -28,9 +27,9 @@ func TestApi_Block(t *testing.T) {
 			case transaction.TypeSellAllCoin:
 				_, ok = data.(*SellAllCoinData)
 			case transaction.TypeBuyCoin:
-				_, ok = data.(*SellCoinData)
-			case transaction.TypeCreateCoin:
 				_, ok = data.(*BuyCoinData)
+			case transaction.TypeCreateCoin:
+				_, ok = data.(*CreateCoinData)
 			case transaction.TypeDeclareCandidacy:
 				_, ok = data.(*DeclareCandidacyData)
 			case transaction.TypeDelegate:
@@ -1,5 +1,3 @@
-// +build integration
-
 package api
 
 import (
@@ -10,6 +8,7 @@ func TestApi_Candidate(t *testing.T) {
 
 	responseCandidates, err := testApi.CandidatesAtHeight(0, true)
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 	if len(responseCandidates) == 0 {
@@ -18,6 +17,7 @@ func TestApi_Candidate(t *testing.T) {
 
 	response, err := testApi.Candidate(responseCandidates[0].PubKey)
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 	t.Logf("%+v", response)
@@ -1,5 +1,3 @@
-// +build integration
-
 package api
 
 import "testing"
@@ -7,6 +5,7 @@ import "testing"
 func TestApi_Candidates(t *testing.T) {
 	response, err := testApi.CandidatesAtHeight(0, true)
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 	t.Logf("%+v", response[0])
@@ -1,5 +1,3 @@
-// +build integration
-
 package api
 
 import (
@@ -9,6 +7,7 @@ import (
 func TestApi_CoinInfo(t *testing.T) {
 	response, err := testApi.CoinInfoAtHeight("CAPITAL", LatestBlockHeight)
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 	t.Logf("%+v", response)
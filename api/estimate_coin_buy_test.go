@@ -1,5 +1,3 @@
-// +build integration
-
 package api
 
 import (
@@ -9,6 +7,7 @@ import (
 func TestApi_EstimateCoinBuy(t *testing.T) {
 	response, err := testApi.EstimateCoinBuyAtHeight("BIP", "1", "MNT", LatestBlockHeight)
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 	t.Logf("%+v", response)
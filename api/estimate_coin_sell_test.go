@@ -1,5 +1,3 @@
-// +build integration
-
 package api
 
 import "testing"
@@ -7,6 +5,7 @@ import "testing"
 func TestApi_EstimateCoinSell(t *testing.T) {
 	response, err := testApi.EstimateCoinSellAtHeight("BIP", "1", "MNT", LatestBlockHeight)
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 	t.Logf("%+v", response)
@@ -1,5 +1,3 @@
-// +build integration
-
 package api
 
 import (
@@ -44,6 +42,7 @@ func TestApi_EstimateTxCommission(t *testing.T) {
 	api := testApi
 	nonce, err := api.Nonce("Mxeeee1973381ab793719fff497b9a516719fcd5a2")
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 
@@ -58,6 +57,7 @@ func TestApi_EstimateTxCommission(t *testing.T) {
 
 	res, err := api.EstimateTxCommission(signedTransaction)
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 	t.Logf("%+v", res)
@@ -98,6 +98,7 @@ func TestApi_EstimateNonSigTxCommission(t *testing.T) {
 	api := testApi
 	nonce, err := api.Nonce("Mxeeee1973381ab793719fff497b9a516719fcd5a2")
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 
@@ -108,6 +109,7 @@ func TestApi_EstimateNonSigTxCommission(t *testing.T) {
 
 	res, err := api.EstimateTxCommission(tx)
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 	t.Logf("%+v", res)
@@ -3,7 +3,10 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math/big"
 	"strconv"
+	"sync"
 )
 
 type EventsResponse struct {
@@ -22,30 +25,77 @@ type Event struct {
 	Value map[string]string `json:"value"`
 }
 
-// Converting event map data to the structure interface regarding event type
-func (e *Event) ValueStruct() (interface{}, error) {
-	bytes, err := json.Marshal(e.Value)
-	if err != nil {
-		return nil, err
+// Types of node events.
+const (
+	EventTypeReward          = "minter/RewardEvent"
+	EventTypeSlash           = "minter/SlashEvent"
+	EventTypeUnbond          = "minter/UnbondEvent"
+	EventTypeCoinLiquidation = "minter/CoinLiquidationEvent"
+	EventTypeStakeKick       = "minter/StakeKickEvent"
+)
+
+var ErrUnknownEventType = errors.New("unknown event type")
+
+// EventDecoder converts event map data to the structure.
+type EventDecoder func(value map[string]string) (interface{}, error)
+
+// Create decoder unmarshalling event map data as JSON to the structure returned by newValue.
+func JSONEventDecoder(newValue func() interface{}) EventDecoder {
+	return func(value map[string]string) (interface{}, error) {
+		bytes, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		v := newValue()
+		err = json.Unmarshal(bytes, v)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
 	}
+}
 
-	var value interface{}
-	switch e.Type {
-	case "minter/RewardEvent":
-		value = &RewardEventValue{}
-	case "minter/SlashEvent":
-		value = &SlashEventValue{}
-	case "minter/UnbondEvent":
-		value = &UnbondEventValue{}
-	default:
-		return nil, errors.New("unknown event type")
+var (
+	eventDecodersMu sync.RWMutex
+	eventDecoders   = map[string]EventDecoder{
+		EventTypeReward:          JSONEventDecoder(func() interface{} { return &RewardEventValue{} }),
+		EventTypeSlash:           JSONEventDecoder(func() interface{} { return &SlashEventValue{} }),
+		EventTypeUnbond:          JSONEventDecoder(func() interface{} { return &UnbondEventValue{} }),
+		EventTypeCoinLiquidation: JSONEventDecoder(func() interface{} { return &CoinLiquidationEventValue{} }),
+		EventTypeStakeKick:       JSONEventDecoder(func() interface{} { return &StakeKickEventValue{} }),
 	}
+)
 
-	err = json.Unmarshal(bytes, value)
-	if err != nil {
-		return nil, err
+// Register decoder of event type used by Event.ValueStruct, it replaces the decoder registered before for the type.
+func RegisterEventDecoder(eventType string, decoder EventDecoder) {
+	eventDecodersMu.Lock()
+	defer eventDecodersMu.Unlock()
+	eventDecoders[eventType] = decoder
+}
+
+// Converting event map data to the structure interface regarding event type
+func (e *Event) ValueStruct() (interface{}, error) {
+	eventDecodersMu.RLock()
+	decoder, ok := eventDecoders[e.Type]
+	eventDecodersMu.RUnlock()
+	if !ok {
+		return nil, ErrUnknownEventType
 	}
 
+	return decoder(e.Value)
+}
+
+// Event value with amount of coin.
+type AmountEventValue interface {
+	// Returns amount as integer in pip.
+	AmountInt() (*big.Int, error)
+}
+
+func parseEventAmount(amount string) (*big.Int, error) {
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid event amount %q", amount)
+	}
 	return value, nil
 }
 
@@ -56,6 +106,10 @@ type RewardEventValue struct {
 	ValidatorPubKey string `json:"validator_pub_key"`
 }
 
+func (v *RewardEventValue) AmountInt() (*big.Int, error) {
+	return parseEventAmount(v.Amount)
+}
+
 type SlashEventValue struct {
 	Address         string `json:"address"`
 	Amount          string `json:"amount"`
@@ -63,6 +117,10 @@ type SlashEventValue struct {
 	ValidatorPubKey string `json:"validator_pub_key"`
 }
 
+func (v *SlashEventValue) AmountInt() (*big.Int, error) {
+	return parseEventAmount(v.Amount)
+}
+
 type UnbondEventValue struct {
 	Address         string `json:"address"`
 	Amount          string `json:"amount"`
@@ -70,6 +128,27 @@ type UnbondEventValue struct {
 	ValidatorPubKey string `json:"validator_pub_key"`
 }
 
+func (v *UnbondEventValue) AmountInt() (*big.Int, error) {
+	return parseEventAmount(v.Amount)
+}
+
+// Coin is liquidated, its reserve fell below the minimal.
+type CoinLiquidationEventValue struct {
+	Coin string `json:"coin"`
+}
+
+// Stake is kicked out of the candidate stakes list by a bigger stake and returned to the address.
+type StakeKickEventValue struct {
+	Address         string `json:"address"`
+	Amount          string `json:"amount"`
+	Coin            string `json:"coin"`
+	ValidatorPubKey string `json:"validator_pub_key"`
+}
+
+func (v *StakeKickEventValue) AmountInt() (*big.Int, error) {
+	return parseEventAmount(v.Amount)
+}
+
 // Returns events at given height.
 func (a *Api) Events() (*EventsResult, error) {
 	return a.EventsAtHeight(LatestBlockHeight)
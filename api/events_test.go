@@ -1,5 +1,3 @@
-// +build integration
-
 package api
 
 import (
@@ -9,6 +7,7 @@ import (
 func TestApi_Events(t *testing.T) {
 	response, err := testApi.EventsAtHeight(12)
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 	for _, v := range response.Events {
@@ -25,6 +24,10 @@ func TestApi_Events(t *testing.T) {
 				_, ok = data.(*SlashEventValue)
 			case "minter/UnbondEvent":
 				_, ok = data.(*UnbondEventValue)
+			case "minter/CoinLiquidationEvent":
+				_, ok = data.(*CoinLiquidationEventValue)
+			case "minter/StakeKickEvent":
+				_, ok = data.(*StakeKickEventValue)
 			default:
 				t.Fatal("not found interface by type")
 			}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEvent_ValueStruct(t *testing.T) {
+	tests := []struct {
+		event  Event
+		amount string
+	}{
+		{Event{Type: EventTypeReward, Value: map[string]string{"role": "DAO", "address": "Mx7f0fc21d932f38ca9444f61703174569066cfa50", "amount": "1000000000000000000", "validator_pub_key": "Mp01"}}, "1000000000000000000"},
+		{Event{Type: EventTypeSlash, Value: map[string]string{"address": "Mx7f0fc21d932f38ca9444f61703174569066cfa50", "amount": "2", "coin": "MNT", "validator_pub_key": "Mp01"}}, "2"},
+		{Event{Type: EventTypeUnbond, Value: map[string]string{"address": "Mx7f0fc21d932f38ca9444f61703174569066cfa50", "amount": "3", "coin": "MNT", "validator_pub_key": "Mp01"}}, "3"},
+		{Event{Type: EventTypeStakeKick, Value: map[string]string{"address": "Mx7f0fc21d932f38ca9444f61703174569066cfa50", "amount": "123456789012345678901234567890", "coin": "TEST", "validator_pub_key": "Mp01"}}, "123456789012345678901234567890"},
+		{Event{Type: EventTypeCoinLiquidation, Value: map[string]string{"coin": "TEST"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.event.Type, func(t *testing.T) {
+			value, err := tt.event.ValueStruct()
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch v := value.(type) {
+			case *StakeKickEventValue:
+				if v.Coin != "TEST" || v.ValidatorPubKey != "Mp01" {
+					t.Errorf("unexpected value %+v", v)
+				}
+			case *CoinLiquidationEventValue:
+				if v.Coin != "TEST" {
+					t.Errorf("unexpected value %+v", v)
+				}
+			}
+
+			amountValue, ok := value.(AmountEventValue)
+			if tt.amount == "" {
+				if ok {
+					t.Errorf("%T should not have amount", value)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("%T has no amount", value)
+			}
+			amount, err := amountValue.AmountInt()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if amount.String() != tt.amount {
+				t.Errorf("amount want %s, got %s", tt.amount, amount)
+			}
+		})
+	}
+}
+
+func TestEvent_ValueStruct_invalidAmount(t *testing.T) {
+	value, err := (&Event{Type: EventTypeReward, Value: map[string]string{"amount": "1.5"}}).ValueStruct()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := value.(AmountEventValue).AmountInt(); err == nil {
+		t.Error("AmountInt want error")
+	}
+}
+
+func TestRegisterEventDecoder(t *testing.T) {
+	const eventType = "minter/TestEvent"
+	event := &Event{Type: eventType, Value: map[string]string{"coin": "mnt"}}
+	t.Cleanup(func() {
+		eventDecodersMu.Lock()
+		defer eventDecodersMu.Unlock()
+		delete(eventDecoders, eventType)
+	})
+
+	_, err := event.ValueStruct()
+	if err != ErrUnknownEventType {
+		t.Fatalf("err want %v, got %v", ErrUnknownEventType, err)
+	}
+
+	type testEventValue struct {
+		Coin string `json:"coin"`
+	}
+	RegisterEventDecoder(eventType, JSONEventDecoder(func() interface{} { return &testEventValue{} }))
+	value, err := event.ValueStruct()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := value.(*testEventValue); !ok || v.Coin != "mnt" {
+		t.Fatalf("unexpected value %#v", value)
+	}
+
+	errInvalid := errors.New("invalid coin")
+	RegisterEventDecoder(eventType, func(value map[string]string) (interface{}, error) {
+		if value["coin"] != strings.ToUpper(value["coin"]) {
+			return nil, errInvalid
+		}
+		return value["coin"], nil
+	})
+	if _, err := event.ValueStruct(); err != errInvalid {
+		t.Errorf("err want %v, got %v", errInvalid, err)
+	}
+}
@@ -0,0 +1,169 @@
+// Package fixture records HTTP traffic of api.Api to golden JSON files and serves them back,
+// for deterministic tests of response parsing with real node payloads.
+//
+//	// record
+//	client := api.NewApiWithClient(nodeURL, resty.New().SetTransport(fixture.NewRecorder("testdata/fixtures", nil)))
+//	// replay
+//	client := api.NewApiWithClient("http://replay", resty.New().SetTransport(fixture.NewReplayer("testdata/fixtures")))
+package fixture
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrNotRecorded is returned by Replayer for requests without fixture.
+var ErrNotRecorded = errors.New("fixture is not recorded")
+
+// Golden file of one request.
+type Fixture struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method string     `json:"method"`
+	Path   string     `json:"path"`
+	Query  url.Values `json:"query,omitempty"`
+}
+
+type Response struct {
+	Status int `json:"status"`
+	// Body is JSON response as is, Text is set instead for responses which are not JSON.
+	Body json.RawMessage `json:"body,omitempty"`
+	Text string          `json:"text,omitempty"`
+}
+
+// Returns file name of fixture of the request: path with query hash, e.g. "address_1a2b3c4d.json".
+// Host is not a part of the name, so fixtures recorded from one node are served for any base URL.
+func Name(req *http.Request) string {
+	name := strings.Trim(req.URL.Path, "/")
+	if name == "" {
+		name = "root"
+	}
+	name = strings.ReplaceAll(name, "/", "_")
+	if req.Method != http.MethodGet {
+		name = strings.ToLower(req.Method) + "_" + name
+	}
+	if query := req.URL.Query(); len(query) != 0 {
+		hash := sha256.Sum256([]byte(query.Encode()))
+		name += "_" + hex.EncodeToString(hash[:4])
+	}
+	return name + ".json"
+}
+
+// Transport recording responses to fixtures in directory. Existing fixtures are overwritten.
+type Recorder struct {
+	dir       string
+	transport http.RoundTripper
+	mu        sync.Mutex
+}
+
+// Create recorder of responses of transport, nil transport means http.DefaultTransport.
+func NewRecorder(dir string, transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{dir: dir, transport: transport}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	fixture := &Fixture{
+		Request:  Request{Method: req.Method, Path: req.URL.Path, Query: req.URL.Query()},
+		Response: Response{Status: res.StatusCode},
+	}
+	if json.Valid(body) {
+		fixture.Response.Body = body
+	} else {
+		fixture.Response.Text = string(body)
+	}
+	if err := r.save(Name(req), fixture); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (r *Recorder) save(name string, fixture *Fixture) error {
+	b, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(r.dir, name), append(b, '\n'), 0644)
+}
+
+// Transport serving responses from fixtures in directory, the network is not used.
+type Replayer struct {
+	dir string
+}
+
+// Create replayer of fixtures in directory.
+func NewReplayer(dir string) *Replayer {
+	return &Replayer{dir: dir}
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	name := Name(req)
+	b, err := ioutil.ReadFile(filepath.Join(r.dir, name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s %s, want file %s", ErrNotRecorded, req.Method, req.URL.RequestURI(), name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	fixture := new(Fixture)
+	if err := json.Unmarshal(b, fixture); err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", name, err)
+	}
+
+	body := []byte(fixture.Response.Text)
+	header := make(http.Header)
+	if fixture.Response.Body != nil {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, fixture.Response.Body); err != nil {
+			return nil, fmt.Errorf("fixture %s: %w", name, err)
+		}
+		body = buf.Bytes()
+		header.Set("Content-Type", "application/json")
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Response.Status, http.StatusText(fixture.Response.Status)),
+		StatusCode:    fixture.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
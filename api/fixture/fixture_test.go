@@ -0,0 +1,71 @@
+package fixture
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestRecorder_Replayer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/status":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"jsonrpc":"2.0","id":"","result":{"latest_block_height":"` + r.URL.Query().Get("height") + `"}}`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("bad gateway"))
+		}
+	}))
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "fixture")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	recorder := &http.Client{Transport: NewRecorder(dir, nil)}
+	replayer := &http.Client{Transport: NewReplayer(dir)}
+	for _, path := range []string{"/status?height=1", "/status?height=2", "/block"} {
+		recorded, err := recorder.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := ioutil.ReadAll(recorded.Body)
+		recorded.Body.Close()
+
+		replayed, err := replayer.Get("http://replay" + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _ := ioutil.ReadAll(replayed.Body)
+		replayed.Body.Close()
+		if replayed.StatusCode != recorded.StatusCode || string(got) != string(want) {
+			t.Errorf("%s: replayed %d %s, recorded %d %s", path, replayed.StatusCode, got, recorded.StatusCode, want)
+		}
+	}
+
+	_, err = replayer.Get("http://replay/status?height=3")
+	if !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("err want %v, got %v", ErrNotRecorded, err)
+	}
+}
+
+func TestName(t *testing.T) {
+	a, _ := http.NewRequest(http.MethodGet, "http://a/address?address=Mx01&height=1", nil)
+	b, _ := http.NewRequest(http.MethodGet, "http://b/address?height=1&address=Mx01", nil)
+	c, _ := http.NewRequest(http.MethodGet, "http://a/address?address=Mx02&height=1", nil)
+	if Name(a) != Name(b) {
+		t.Errorf("names of the same request differ: %s, %s", Name(a), Name(b))
+	}
+	if Name(a) == Name(c) {
+		t.Errorf("names of different requests are equal: %s", Name(a))
+	}
+	status, _ := http.NewRequest(http.MethodGet, "http://a/status", nil)
+	if Name(status) != "status.json" {
+		t.Errorf("name want status.json, got %s", Name(status))
+	}
+}
@@ -10,6 +10,18 @@
 		// Result: &api_pb.StatusResponse{Version:"1.1.6-testnet", LatestBlockHash:"A02D691E2AC87EF1847B1D89D0283D44AE83654A9A1643B9EC9551D5E2D0D647", LatestAppHash:"C5E19AD5E7BC3A77EFB7385CAF2FEE78917628A35104CBC32723D7B2E666C087", LatestBlockHeight:"16491", LatestBlockTime:"2020-04-09T11:27:24.530403396+03:00", KeepLastStates:"120", CatchingUp:false, PublicKey:"Mp0d29a83e54653a1d5f34e561e0135f1e81cbcae152f1f327ab36857a7e32de4c", NodeId:"4735e67924e611b89fbd3f951441b5e912e226d3", XXX_NoUnkeyedLiteral:struct {}{}, XXX_unrecognized:[]uint8(nil), XXX_sizecache:0}
 
 
+		client, err := grpc_client.NewWithOptions(grpcAddress,
+			grpc_client.WithTLS(nil),
+			grpc_client.WithTimeout(5*time.Second),
+			grpc_client.WithKeepalive(keepalive.ClientParameters{Time: time.Minute}),
+		)
+		if err != nil {
+			fmt.Print(err)
+			return
+		}
+		defer client.Close()
+
+
 		f := func(c context.Context) func() context.Context {
 			return func() context.Context {
 				ctx, _ := context.WithTimeout(c, time.Second)
@@ -97,5 +109,16 @@
 		//&api_pb.SubscribeResponse_Event{Key:"tx.hash", Events:[]string{"60780B4E54982C645B3D613FC83203C122CF3EF9FBD9394E564F8AD2E79469F7"}, XXX_NoUnkeyedLiteral:struct {}{}, XXX_unrecognized:[]uint8(nil), XXX_sizecache:0}
 		//&api_pb.SubscribeResponse_Event{Key:"tx.height", Events:[]string{"23328"}, XXX_NoUnkeyedLiteral:struct {}{}, XXX_unrecognized:[]uint8(nil), XXX_sizecache:0}
 
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		subscription := client.SubscribeEvents(ctx, "tm.event='Tx' AND tags.tx.type='01'",
+			grpc_client.WithErrorHandler(func(err error) { log.Println("reconnecting:", err) }),
+		)
+		for event := range subscription.Events() {
+			fmt.Println(event.Height, event.Transaction.Hash, event.Backfilled)
+		}
+		fmt.Println(subscription.Err())
+
 */
 package grpc_client
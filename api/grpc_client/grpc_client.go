@@ -10,20 +10,45 @@ import (
 
 type Client struct {
 	grpcClient api_pb.ApiServiceClient
+	conn       *grpc.ClientConn
 	ctxFunc    func() context.Context
 }
 
+// Create client with insecure connection. It panics if address is invalid, use NewWithOptions to get an error.
 func New(address string) *Client {
-	clientConn, err := grpc.Dial(address, grpc.WithInsecure())
+	client, err := NewWithOptions(address)
 	if err != nil {
 		panic(err)
 	}
 
-	return &Client{grpcClient: api_pb.NewApiServiceClient(clientConn), ctxFunc: context.Background}
+	return client
+}
+
+// Create client with options. The connection is established lazily, add grpc.WithBlock() dial option to wait for it.
+func NewWithOptions(address string, opts ...Option) (*Client, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	clientConn, err := grpc.Dial(address, o.grpcDialOptions()...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{grpcClient: api_pb.NewApiServiceClient(clientConn), conn: clientConn, ctxFunc: context.Background}, nil
+}
+
+// Close the underlying connection. Clients derived with WithContextFunc share the connection and are closed too.
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
 }
 
 func (c *Client) WithContextFunc(contextFunc func(context.Context) func() context.Context) *Client {
-	return &Client{grpcClient: c.grpcClient, ctxFunc: contextFunc(c.ctxFunc())}
+	return &Client{grpcClient: c.grpcClient, conn: c.conn, ctxFunc: contextFunc(c.ctxFunc())}
 }
 
 func (c *Client) GRPCClient() api_pb.ApiServiceClient {
@@ -0,0 +1,525 @@
+// Package grpctest provides in-memory fake Minter node implementing api_pb.ApiServiceServer over bufconn,
+// backed by apitest.Ledger, for offline tests of grpc_client.Client and code using it.
+//
+//	server := grpctest.NewServer(nil)
+//	defer server.Close()
+//	client, err := grpc_client.NewWithOptions("bufnet", grpc_client.WithDialOptions(server.DialOption()))
+package grpctest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/MinterTeam/node-grpc-gateway/api_pb"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes/empty"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/nikolaev-dev/sdk/api"
+	"github.com/nikolaev-dev/sdk/api/apitest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fake node gRPC server.
+type Server struct {
+	api_pb.UnimplementedApiServiceServer
+	Ledger *apitest.Ledger
+
+	listener *bufconn.Listener
+	server   *grpc.Server
+
+	mu            sync.Mutex
+	subscriptions map[*subscription]bool
+	changed       chan struct{}
+}
+
+type subscription struct {
+	query  string
+	parsed *apitest.Query
+	events chan *api_pb.SubscribeResponse
+	// receives the error to end the stream with
+	done chan error
+}
+
+// Start fake node server with ledger, nil ledger is replaced with apitest.NewLedger().
+// Tx and NewBlock events of blocks committed by the ledger are published to subscriptions.
+func NewServer(ledger *apitest.Ledger) *Server {
+	if ledger == nil {
+		ledger = apitest.NewLedger()
+	}
+	s := &Server{
+		Ledger:        ledger,
+		listener:      bufconn.Listen(1 << 20),
+		server:        grpc.NewServer(),
+		subscriptions: make(map[*subscription]bool),
+		changed:       make(chan struct{}),
+	}
+	ledger.OnCommit(s.publishBlock)
+	api_pb.RegisterApiServiceServer(s.server, s)
+	go s.server.Serve(s.listener)
+	return s
+}
+
+// Returns dialer of in-memory connections to the server.
+func (s *Server) Dialer() func(context.Context, string) (net.Conn, error) {
+	return func(ctx context.Context, _ string) (net.Conn, error) {
+		return s.listener.Dial()
+	}
+}
+
+// Returns dial option of client connection to the server, any address can be dialed with it.
+func (s *Server) DialOption() grpc.DialOption {
+	return grpc.WithContextDialer(s.Dialer())
+}
+
+// Stop the server, open subscriptions are closed.
+func (s *Server) Close() {
+	s.server.Stop()
+}
+
+// Returns number of open subscriptions.
+func (s *Server) Subscribers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subscriptions)
+}
+
+// Wait until at least n subscriptions are open.
+func (s *Server) WaitSubscribers(ctx context.Context, n int) error {
+	for {
+		s.mu.Lock()
+		count, changed := len(s.subscriptions), s.changed
+		s.mu.Unlock()
+		if count >= n {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// Send event to open subscriptions with matching query. Conditions of queries joined with AND are matched
+// against events keys, e.g. "tm.event='Tx' AND tags.tx.type='01'".
+func (s *Server) Publish(event *api_pb.SubscribeResponse) {
+	values := make(map[string][]string, len(event.Events))
+	for _, e := range event.Events {
+		values[e.Key] = append(values[e.Key], e.Events...)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscriptions {
+		if !sub.parsed.Match(values) {
+			continue
+		}
+		response := *event
+		response.Query = sub.query
+		select {
+		case sub.events <- &response:
+		default:
+			// slow subscriber is disconnected like by the node
+			s.remove(sub, status.Error(codes.ResourceExhausted, "subscription buffer is full"))
+		}
+	}
+}
+
+// End open subscriptions with err, nil err ends streams normally. Clients see the broken stream and reconnect.
+func (s *Server) Disconnect(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscriptions {
+		s.remove(sub, err)
+	}
+}
+
+func (s *Server) remove(sub *subscription, err error) {
+	if !s.subscriptions[sub] {
+		return
+	}
+	delete(s.subscriptions, sub)
+	sub.done <- err
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *Server) publishBlock(block *api.BlockResult) {
+	for i := range block.Transactions {
+		s.Publish(TxEvent(&block.Transactions[i]))
+	}
+	height, _ := strconv.Atoi(block.Height)
+	s.Publish(NewBlockEvent(height))
+}
+
+// Returns event of transaction as the node sends it to subscriptions.
+func TxEvent(tx *api.TransactionResult) *api_pb.SubscribeResponse {
+	events := apitest.TransactionKeys(tx)
+	events["tm.event"] = []string{"Tx"}
+	response := &api_pb.SubscribeResponse{Data: &_struct.Struct{Fields: map[string]*_struct.Value{
+		"height": stringValue(tx.Height),
+		"index":  {Kind: &_struct.Value_NumberValue{NumberValue: float64(tx.Index)}},
+		"tx":     stringValue(tx.RawTx),
+	}}}
+	for key, value := range events {
+		response.Events = append(response.Events, &api_pb.SubscribeResponse_Event{Key: key, Events: value})
+	}
+	return response
+}
+
+// Returns event of new block at height as the node sends it to subscriptions.
+func NewBlockEvent(height int) *api_pb.SubscribeResponse {
+	header := &_struct.Struct{Fields: map[string]*_struct.Value{"height": stringValue(strconv.Itoa(height))}}
+	block := &_struct.Struct{Fields: map[string]*_struct.Value{"header": {Kind: &_struct.Value_StructValue{StructValue: header}}}}
+	return &api_pb.SubscribeResponse{
+		Data:   &_struct.Struct{Fields: map[string]*_struct.Value{"block": {Kind: &_struct.Value_StructValue{StructValue: block}}}},
+		Events: []*api_pb.SubscribeResponse_Event{{Key: "tm.event", Events: []string{"NewBlock"}}},
+	}
+}
+
+func (s *Server) Subscribe(req *api_pb.SubscribeRequest, stream api_pb.ApiService_SubscribeServer) error {
+	query, err := apitest.ParseQuery(req.Query)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	sub := &subscription{query: req.Query, parsed: query, events: make(chan *api_pb.SubscribeResponse, 100), done: make(chan error, 1)}
+	s.mu.Lock()
+	s.subscriptions[sub] = true
+	close(s.changed)
+	s.changed = make(chan struct{})
+	s.mu.Unlock()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			s.mu.Lock()
+			s.remove(sub, nil)
+			s.mu.Unlock()
+			return stream.Context().Err()
+		case event := <-sub.events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		case err := <-sub.done:
+			// deliver events published before the end
+			for {
+				select {
+				case event := <-sub.events:
+					if err := stream.Send(event); err != nil {
+						return err
+					}
+					continue
+				default:
+				}
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) Halts(context.Context, *api_pb.HaltsRequest) (*api_pb.HaltsResponse, error) {
+	return &api_pb.HaltsResponse{}, nil
+}
+
+func (s *Server) Genesis(context.Context, *empty.Empty) (*api_pb.GenesisResponse, error) {
+	return &api_pb.GenesisResponse{GenesisTime: apitest.GenesisTime.Format(time.RFC3339Nano), ChainId: "minter-test"}, nil
+}
+
+func (s *Server) MinGasPrice(context.Context, *empty.Empty) (*api_pb.MinGasPriceResponse, error) {
+	minGasPrice, _ := s.Ledger.Gas()
+	return &api_pb.MinGasPriceResponse{MinGasPrice: strconv.Itoa(minGasPrice)}, nil
+}
+
+func (s *Server) NetInfo(context.Context, *empty.Empty) (*api_pb.NetInfoResponse, error) {
+	return &api_pb.NetInfoResponse{Listening: true, CountPeers: "0"}, nil
+}
+
+func (s *Server) Status(context.Context, *empty.Empty) (*api_pb.StatusResponse, error) {
+	block, _ := s.Ledger.Block(s.Ledger.Height())
+	return &api_pb.StatusResponse{
+		Version:           "grpctest",
+		LatestBlockHash:   block.Hash,
+		LatestAppHash:     block.Hash,
+		LatestBlockHeight: block.Height,
+		LatestBlockTime:   block.Time.Format(time.RFC3339Nano),
+		KeepLastStates:    "120",
+	}, nil
+}
+
+func (s *Server) Address(_ context.Context, req *api_pb.AddressRequest) (*api_pb.AddressResponse, error) {
+	address := s.Ledger.Address(req.Address)
+	return &api_pb.AddressResponse{Balance: address.Balance, TransactionsCount: address.TransactionCount}, nil
+}
+
+func (s *Server) Addresses(_ context.Context, req *api_pb.AddressesRequest) (*api_pb.AddressesResponse, error) {
+	response := &api_pb.AddressesResponse{}
+	for _, a := range req.Addresses {
+		address := s.Ledger.Address(a)
+		response.Addresses = append(response.Addresses, &api_pb.AddressesResponse_Result{Address: a, Balance: address.Balance, TransactionsCount: address.TransactionCount})
+	}
+	return response, nil
+}
+
+func (s *Server) Block(_ context.Context, req *api_pb.BlockRequest) (*api_pb.BlockResponse, error) {
+	block, ok := s.Ledger.Block(int(req.Height))
+	if !ok {
+		return nil, status.Error(codes.NotFound, "Block not found")
+	}
+	response := &api_pb.BlockResponse{
+		Hash:              block.Hash,
+		Height:            block.Height,
+		Time:              block.Time.Format(time.RFC3339Nano),
+		TransactionsCount: block.NumTxs,
+		BlockReward:       block.BlockReward,
+		Size:              block.Size,
+		Proposer:          block.Proposer,
+	}
+	for i := range block.Transactions {
+		tx, err := transactionResponse(&block.Transactions[i])
+		if err != nil {
+			return nil, err
+		}
+		response.Transactions = append(response.Transactions, &api_pb.BlockResponse_Transaction{
+			Hash:        tx.Hash,
+			RawTx:       tx.RawTx,
+			From:        tx.From,
+			Nonce:       tx.Nonce,
+			GasPrice:    tx.GasPrice,
+			Type:        tx.Type,
+			Data:        tx.Data,
+			Payload:     tx.Payload,
+			ServiceData: block.Transactions[i].ServiceData,
+			Gas:         tx.Gas,
+			GasCoin:     tx.GasCoin,
+			Tags:        tx.Tags,
+			Code:        tx.Code,
+			Log:         tx.Log,
+		})
+	}
+	for _, validator := range block.Validators {
+		response.Validators = append(response.Validators, &api_pb.BlockResponse_Validator{PublicKey: validator.PubKey, Signed: validator.Signed})
+	}
+	return response, nil
+}
+
+func (s *Server) Candidate(_ context.Context, req *api_pb.CandidateRequest) (*api_pb.CandidateResponse, error) {
+	candidate, ok := s.Ledger.Candidate(req.PublicKey)
+	if !ok {
+		return nil, status.Error(codes.NotFound, "Candidate not found")
+	}
+	return candidateResponse(candidate), nil
+}
+
+func (s *Server) Candidates(_ context.Context, req *api_pb.CandidatesRequest) (*api_pb.CandidatesResponse, error) {
+	response := &api_pb.CandidatesResponse{}
+	for _, candidate := range s.Ledger.Candidates(req.IncludeStakes) {
+		response.Candidates = append(response.Candidates, candidateResponse(candidate))
+	}
+	return response, nil
+}
+
+func (s *Server) CoinInfo(_ context.Context, req *api_pb.CoinInfoRequest) (*api_pb.CoinInfoResponse, error) {
+	coin, ok := s.Ledger.Coin(req.Symbol)
+	if !ok {
+		return nil, status.Error(codes.FailedPrecondition, "Coin not found")
+	}
+	return &api_pb.CoinInfoResponse{Name: coin.Name, Symbol: coin.Symbol, Volume: coin.Volume, Crr: coin.Crr, ReserveBalance: coin.ReserveBalance}, nil
+}
+
+func (s *Server) estimate(coinToSell, coinToBuy, value string) (string, string, error) {
+	amount, commission, err := s.Ledger.Estimate(coinToSell, coinToBuy, value)
+	if err == apitest.ErrCoinNotFound {
+		return "", "", status.Error(codes.FailedPrecondition, "Coin not found")
+	}
+	if err != nil {
+		return "", "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	return amount.String(), commission.String(), nil
+}
+
+func (s *Server) EstimateCoinBuy(_ context.Context, req *api_pb.EstimateCoinBuyRequest) (*api_pb.EstimateCoinBuyResponse, error) {
+	willPay, commission, err := s.estimate(req.CoinToSell, req.CoinToBuy, req.ValueToBuy)
+	if err != nil {
+		return nil, err
+	}
+	return &api_pb.EstimateCoinBuyResponse{WillPay: willPay, Commission: commission}, nil
+}
+
+func (s *Server) EstimateCoinSell(_ context.Context, req *api_pb.EstimateCoinSellRequest) (*api_pb.EstimateCoinSellResponse, error) {
+	willGet, commission, err := s.estimate(req.CoinToSell, req.CoinToBuy, req.ValueToSell)
+	if err != nil {
+		return nil, err
+	}
+	return &api_pb.EstimateCoinSellResponse{WillGet: willGet, Commission: commission}, nil
+}
+
+func (s *Server) EstimateCoinSellAll(_ context.Context, req *api_pb.EstimateCoinSellAllRequest) (*api_pb.EstimateCoinSellAllResponse, error) {
+	willGet, _, err := s.estimate(req.CoinToSell, req.CoinToBuy, req.ValueToSell)
+	if err != nil {
+		return nil, err
+	}
+	return &api_pb.EstimateCoinSellAllResponse{WillGet: willGet}, nil
+}
+
+func (s *Server) EstimateTxCommission(_ context.Context, req *api_pb.EstimateTxCommissionRequest) (*api_pb.EstimateTxCommissionResponse, error) {
+	commission, err := s.Ledger.EstimateTxCommission(req.Tx)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &api_pb.EstimateTxCommissionResponse{Commission: commission.String()}, nil
+}
+
+func (s *Server) Events(_ context.Context, req *api_pb.EventsRequest) (*api_pb.EventsResponse, error) {
+	height := int(req.Height)
+	if height == 0 {
+		height = s.Ledger.Height()
+	}
+	response := &api_pb.EventsResponse{}
+	for _, event := range s.Ledger.Events(height) {
+		value := &_struct.Struct{Fields: make(map[string]*_struct.Value, len(event.Value))}
+		for key, v := range event.Value {
+			value.Fields[key] = stringValue(v)
+		}
+		response.Events = append(response.Events, &api_pb.EventsResponse_Event{Type: event.Type, Value: value})
+	}
+	return response, nil
+}
+
+func (s *Server) MaxGas(context.Context, *api_pb.MaxGasRequest) (*api_pb.MaxGasResponse, error) {
+	_, maxGas := s.Ledger.Gas()
+	return &api_pb.MaxGasResponse{MaxGas: strconv.Itoa(maxGas)}, nil
+}
+
+func (s *Server) MissedBlocks(_ context.Context, req *api_pb.MissedBlocksRequest) (*api_pb.MissedBlocksResponse, error) {
+	if _, ok := s.Ledger.Candidate(req.PublicKey); !ok {
+		return nil, status.Error(codes.NotFound, "Validator not found")
+	}
+	return &api_pb.MissedBlocksResponse{MissedBlocks: strings.Repeat("x", 24), MissedBlocksCount: "0"}, nil
+}
+
+// Check errors are returned with code FailedPrecondition and details {"code": code, "log": log} of the node check result.
+func (s *Server) SendGetTransaction(_ context.Context, req *api_pb.SendTransactionRequest) (*api_pb.SendTransactionResponse, error) {
+	tx, err := s.Ledger.Apply(req.Tx)
+	if err != nil {
+		txErr := err.(*api.TxError)
+		st, detailsErr := status.New(codes.FailedPrecondition, txErr.TxResult.Log).WithDetails(&_struct.Struct{Fields: map[string]*_struct.Value{
+			"code": stringValue(strconv.Itoa(txErr.TxResult.Code)),
+			"log":  stringValue(txErr.TxResult.Log),
+		}})
+		if detailsErr != nil {
+			return nil, detailsErr
+		}
+		return nil, st.Err()
+	}
+	return &api_pb.SendTransactionResponse{Code: "0", Hash: "Mt" + strings.ToLower(strings.TrimPrefix(tx.Hash, "Mt"))}, nil
+}
+
+func (s *Server) SendPostTransaction(ctx context.Context, req *api_pb.SendTransactionRequest) (*api_pb.SendTransactionResponse, error) {
+	return s.SendGetTransaction(ctx, req)
+}
+
+func (s *Server) Transaction(_ context.Context, req *api_pb.TransactionRequest) (*api_pb.TransactionResponse, error) {
+	tx, ok := s.Ledger.Transaction(req.Hash)
+	if !ok {
+		return nil, status.Error(codes.NotFound, "Transaction not found")
+	}
+	return transactionResponse(tx)
+}
+
+func (s *Server) Transactions(_ context.Context, req *api_pb.TransactionsRequest) (*api_pb.TransactionsResponse, error) {
+	transactions, err := s.Ledger.Transactions(req.Query)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	from, to, err := apitest.Page(strconv.Itoa(int(req.Page)), strconv.Itoa(int(req.PerPage)), len(transactions))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	response := &api_pb.TransactionsResponse{}
+	for _, tx := range transactions[from:to] {
+		t, err := transactionResponse(tx)
+		if err != nil {
+			return nil, err
+		}
+		response.Transactions = append(response.Transactions, t)
+	}
+	return response, nil
+}
+
+func (s *Server) UnconfirmedTxs(context.Context, *api_pb.UnconfirmedTxsRequest) (*api_pb.UnconfirmedTxsResponse, error) {
+	return &api_pb.UnconfirmedTxsResponse{TransactionsCount: "0", TotalTransactions: "0", TotalBytes: "0"}, nil
+}
+
+func (s *Server) Validators(_ context.Context, req *api_pb.ValidatorsRequest) (*api_pb.ValidatorsResponse, error) {
+	validators := s.Ledger.Validators()
+	from, to, err := apitest.Page(strconv.Itoa(int(req.Page)), strconv.Itoa(int(req.PerPage)), len(validators))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	response := &api_pb.ValidatorsResponse{}
+	for _, validator := range validators[from:to] {
+		response.Validators = append(response.Validators, &api_pb.ValidatorsResponse_Result{PublicKey: validator.PubKey, VotingPower: validator.VotingPower})
+	}
+	return response, nil
+}
+
+func candidateResponse(candidate *api.CandidateResult) *api_pb.CandidateResponse {
+	response := &api_pb.CandidateResponse{
+		RewardAddress: candidate.RewardAddress,
+		TotalStake:    candidate.TotalStake,
+		PublicKey:     candidate.PubKey,
+		Commission:    candidate.Commission,
+		Status:        strconv.Itoa(candidate.Status),
+	}
+	for _, stake := range candidate.Stakes {
+		response.Stakes = append(response.Stakes, &api_pb.CandidateResponse_Stake{Owner: stake.Owner, Coin: stake.Coin, Value: stake.Value, BipValue: stake.BipValue})
+	}
+	return response
+}
+
+func transactionResponse(tx *api.TransactionResult) (*api_pb.TransactionResponse, error) {
+	tags := make(map[string]string)
+	for key, values := range apitest.TransactionKeys(tx) {
+		if strings.HasPrefix(key, "tags.") {
+			tags[strings.TrimPrefix(key, "tags.")] = values[0]
+		}
+	}
+	response := &api_pb.TransactionResponse{
+		Hash:     tx.Hash,
+		RawTx:    tx.RawTx,
+		Height:   tx.Height,
+		Index:    strconv.Itoa(tx.Index),
+		From:     tx.From,
+		Nonce:    tx.Nonce,
+		Gas:      tx.Gas,
+		GasPrice: strconv.Itoa(tx.GasPrice),
+		GasCoin:  tx.GasCoin,
+		Type:     strconv.Itoa(tx.Type),
+		Payload:  tx.Payload,
+		Tags:     tags,
+		Code:     strconv.Itoa(int(tx.Code)),
+		Log:      tx.Log,
+	}
+	if tx.Data != nil {
+		b, err := json.Marshal(tx.Data)
+		if err != nil {
+			return nil, err
+		}
+		response.Data = &_struct.Struct{}
+		if err := jsonpb.Unmarshal(bytes.NewReader(b), response.Data); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+func stringValue(value string) *_struct.Value {
+	return &_struct.Value{Kind: &_struct.Value_StringValue{StringValue: value}}
+}
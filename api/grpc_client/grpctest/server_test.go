@@ -0,0 +1,184 @@
+package grpctest
+
+import (
+	"context"
+	"errors"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/nikolaev-dev/sdk/api"
+	"github.com/nikolaev-dev/sdk/api/grpc_client"
+	"github.com/nikolaev-dev/sdk/transaction"
+	"github.com/nikolaev-dev/sdk/wallet"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const (
+	privateKey = "07bc17abdcee8b971bb8723e36fe9d2523306d5ab2d683631693238e0f9df142"
+	recipient  = "Mx1b685a7c1e78726c48f619c497a07ed75fe00483"
+)
+
+func newClient(t *testing.T) (*Server, *grpc_client.Client, string) {
+	server := NewServer(nil)
+	t.Cleanup(server.Close)
+	client, err := grpc_client.NewWithOptions("bufnet", grpc_client.WithDialOptions(server.DialOption()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	publicKey, err := wallet.PublicKeyByPrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, err := wallet.AddressByPublicKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Ledger.SetBalance(sender, "MNT", big.NewInt(0).Exp(big.NewInt(10), big.NewInt(18), nil))
+	return server, client, sender
+}
+
+func signedSend(t *testing.T, nonce uint64) string {
+	data, err := transaction.NewSendData().SetCoin("MNT").SetValue(big.NewInt(1)).SetTo(recipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := transaction.NewBuilder(transaction.TestNetChainID).NewTransaction(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := tx.SetNonce(nonce).SetGasPrice(1).SetGasCoin("MNT").Sign(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := signed.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded
+}
+
+func TestServer_SendTransaction(t *testing.T) {
+	_, client, sender := newClient(t)
+	node := client.NodeClient()
+
+	nonce, err := node.Nonce(sender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := node.SendRawTransaction(signedSend(t, nonce))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := node.Transaction(result.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.From != sender || tx.Height != "2" || tx.Type != int(transaction.TypeSend) || tx.Tags.TxTo != "1b685a7c1e78726c48f619c497a07ed75fe00483" {
+		t.Errorf("unexpected transaction %+v", tx)
+	}
+	data, err := tx.DataStruct()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if send := data.(*api.SendData); send.To != recipient || send.Value != "1" {
+		t.Errorf("unexpected data %+v", send)
+	}
+
+	block, err := node.Block(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(block.Transactions) != 1 || block.Transactions[0].Hash != tx.Hash {
+		t.Errorf("unexpected block %+v", block)
+	}
+	if nonce, err := node.Nonce(sender); err != nil || nonce != 2 {
+		t.Errorf("nonce want 2, got %d, %v", nonce, err)
+	}
+
+	_, err = client.SendTransaction(signedSend(t, 1))
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.FailedPrecondition || len(st.Details()) != 1 {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if code := st.Details()[0].(*_struct.Struct).Fields["code"].GetStringValue(); code != "101" {
+		t.Errorf("check code want 101, got %s", code)
+	}
+
+	if _, err := client.CoinInfo("TEST"); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("coin info want FailedPrecondition, got %v", err)
+	}
+}
+
+func TestServer_Subscribe(t *testing.T) {
+	server, client, sender := newClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	subscription := client.SubscribeEvents(ctx, "tm.event='Tx' AND tags.tx.type='01'", grpc_client.WithBackoff(time.Millisecond, time.Millisecond))
+	if err := server.WaitSubscribers(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := server.Ledger.Apply(signedSend(t, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := <-subscription.Events()
+	if event == nil || event.Transaction.Hash != applied.Hash || event.Backfilled {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	// the transaction committed around reconnection is delivered once, live or back-filled
+	server.Disconnect(errors.New("connection reset"))
+	applied, err = server.Ledger.Apply(signedSend(t, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	event = <-subscription.Events()
+	if event == nil || event.Height != 3 || event.Transaction.Hash != applied.Hash {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	if err := server.WaitSubscribers(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// events not matching the query: new block and transaction without tags.tx.type
+	server.Publish(NewBlockEvent(4))
+	server.Publish(TxEvent(&api.TransactionResult{Hash: "Mt01", Height: "4"}))
+	if _, err := server.Ledger.Apply(signedSend(t, 3)); err != nil {
+		t.Fatal(err)
+	}
+	event = <-subscription.Events()
+	if event == nil || event.Height != 4 || event.Transaction.From != sender {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	cancel()
+	for range subscription.Events() {
+	}
+}
+
+func TestServer_Validators(t *testing.T) {
+	server, client, _ := newClient(t)
+	for i := 0; i < 2*api.DefaultPerPage+1; i++ {
+		server.Ledger.AddValidator(api.ValidatorResult{PubKey: "Mp" + strconv.Itoa(1000+i), VotingPower: "1"})
+	}
+
+	count := 0
+	it := api.NewValidatorsIterator(client.NodeClient(), 0, 0)
+	for it.Next(context.Background()) {
+		count++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2*api.DefaultPerPage+1 {
+		t.Errorf("validators want %d, got %d", 2*api.DefaultPerPage+1, count)
+	}
+}
@@ -0,0 +1,423 @@
+package grpc_client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/MinterTeam/node-grpc-gateway/api_pb"
+	"github.com/golang/protobuf/jsonpb"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/nikolaev-dev/sdk/api"
+	"strconv"
+	"time"
+)
+
+// Create gRPC implementation of api.NodeClient.
+func NewNodeClient(address string, opts ...Option) (api.NodeClient, error) {
+	client, err := NewWithOptions(address, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return client.NodeClient(), nil
+}
+
+// Returns api.NodeClient adapter of the client with results converted to the api package types.
+func (c *Client) NodeClient() api.NodeClient {
+	return &nodeClient{client: c}
+}
+
+type nodeClient struct {
+	client *Client
+}
+
+type (
+	candidateStake = struct {
+		Owner    string `json:"owner"`
+		Coin     string `json:"coin"`
+		Value    string `json:"value"`
+		BipValue string `json:"bip_value"`
+	}
+	blockValidator = struct {
+		PubKey string `json:"pub_key"`
+		Signed bool   `json:"signed"`
+	}
+)
+
+func (n *nodeClient) Status() (*api.StatusResult, error) {
+	response, err := n.client.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	latestBlockTime, err := parseTime(response.LatestBlockTime)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api.StatusResult{
+		Version:           response.Version,
+		LatestBlockHash:   response.LatestBlockHash,
+		LatestAppHash:     response.LatestAppHash,
+		LatestBlockHeight: response.LatestBlockHeight,
+		LatestBlockTime:   latestBlockTime,
+		StateHistory:      response.KeepLastStates,
+	}
+	result.TmStatus.NodeInfo.ID = response.NodeId
+	result.TmStatus.SyncInfo.LatestBlockHash = response.LatestBlockHash
+	result.TmStatus.SyncInfo.LatestAppHash = response.LatestAppHash
+	result.TmStatus.SyncInfo.LatestBlockHeight = response.LatestBlockHeight
+	result.TmStatus.SyncInfo.LatestBlockTime = latestBlockTime
+	result.TmStatus.SyncInfo.CatchingUp = response.CatchingUp
+	return result, nil
+}
+
+func (n *nodeClient) AddressAtHeight(address string, height int) (*api.AddressResult, error) {
+	response, err := n.client.Address(address, height)
+	if err != nil {
+		return nil, err
+	}
+	return &api.AddressResult{Balance: response.Balance, TransactionCount: response.TransactionsCount}, nil
+}
+
+func (n *nodeClient) Nonce(address string) (uint64, error) {
+	return n.client.Nonce(address)
+}
+
+func (n *nodeClient) Block(height int) (*api.BlockResult, error) {
+	response, err := n.client.Block(height)
+	if err != nil {
+		return nil, err
+	}
+
+	blockTime, err := parseTime(response.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	// gRPC block has no count of transactions in the chain, so TotalTxs is left empty
+	result := &api.BlockResult{
+		Hash:         response.Hash,
+		Height:       response.Height,
+		Time:         blockTime,
+		NumTxs:       response.TransactionsCount,
+		Transactions: make([]api.TransactionResult, 0, len(response.Transactions)),
+		BlockReward:  response.BlockReward,
+		Size:         response.Size,
+		Proposer:     response.Proposer,
+	}
+	for i, tx := range response.Transactions {
+		transaction, err := transactionResult(&api_pb.TransactionResponse{
+			Hash:     tx.Hash,
+			RawTx:    tx.RawTx,
+			Height:   response.Height,
+			Index:    strconv.Itoa(i),
+			From:     tx.From,
+			Nonce:    tx.Nonce,
+			Gas:      tx.Gas,
+			GasPrice: tx.GasPrice,
+			GasCoin:  tx.GasCoin,
+			Type:     tx.Type,
+			Data:     tx.Data,
+			Payload:  tx.Payload,
+			Tags:     tx.Tags,
+			Code:     tx.Code,
+			Log:      tx.Log,
+		})
+		if err != nil {
+			return nil, err
+		}
+		transaction.ServiceData = tx.ServiceData
+		result.Transactions = append(result.Transactions, *transaction)
+	}
+	for _, validator := range response.Validators {
+		result.Validators = append(result.Validators, blockValidator{PubKey: validator.PublicKey, Signed: validator.Signed})
+	}
+	return result, nil
+}
+
+func (n *nodeClient) CandidateAtHeight(pubKey string, height int) (*api.CandidateResult, error) {
+	response, err := n.client.Candidate(pubKey, height)
+	if err != nil {
+		return nil, err
+	}
+	return candidateResult(response)
+}
+
+func (n *nodeClient) CandidatesAtHeight(height int, includeStakes bool) ([]*api.CandidateResult, error) {
+	response, err := n.client.Candidates(includeStakes, height)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*api.CandidateResult, 0, len(response.Candidates))
+	for _, candidate := range response.Candidates {
+		c, err := candidateResult(candidate)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+func (n *nodeClient) CoinInfoAtHeight(symbol string, height int) (*api.CoinInfoResult, error) {
+	response, err := n.client.CoinInfo(symbol, height)
+	if err != nil {
+		return nil, err
+	}
+	return &api.CoinInfoResult{
+		Name:           response.Name,
+		Symbol:         response.Symbol,
+		Volume:         response.Volume,
+		Crr:            response.Crr,
+		ReserveBalance: response.ReserveBalance,
+	}, nil
+}
+
+func (n *nodeClient) EstimateCoinBuyAtHeight(coinToSell string, valueToBuy string, coinToBuy string, height int) (*api.EstimateCoinBuyResult, error) {
+	response, err := n.client.EstimateCoinBuy(coinToSell, coinToBuy, valueToBuy, height)
+	if err != nil {
+		return nil, err
+	}
+	return &api.EstimateCoinBuyResult{WillPay: response.WillPay, Commission: response.Commission}, nil
+}
+
+func (n *nodeClient) EstimateCoinSellAtHeight(coinToSell string, valueToSell string, coinToBuy string, height int) (*api.EstimateCoinSellResult, error) {
+	response, err := n.client.EstimateCoinSell(coinToBuy, coinToSell, valueToSell, height)
+	if err != nil {
+		return nil, err
+	}
+	return &api.EstimateCoinSellResult{WillGet: response.WillGet, Commission: response.Commission}, nil
+}
+
+func (n *nodeClient) EstimateCoinSellAllAtHeight(coinToSell string, coinToBuy string, valueToSell string, gasPrice int, height int) (*api.EstimateCoinSellAllResult, error) {
+	response, err := n.client.EstimateCoinSellAll(coinToBuy, coinToSell, valueToSell, gasPrice, height)
+	if err != nil {
+		return nil, err
+	}
+	return &api.EstimateCoinSellAllResult{WillGet: response.WillGet}, nil
+}
+
+func (n *nodeClient) EventsAtHeight(height int) (*api.EventsResult, error) {
+	response, err := n.client.Events(height)
+	if err != nil {
+		return nil, err
+	}
+	result := &api.EventsResult{Events: make([]api.Event, 0, len(response.Events))}
+	for _, event := range response.Events {
+		value, err := structToStrings(event.Value)
+		if err != nil {
+			return nil, err
+		}
+		result.Events = append(result.Events, api.Event{Type: event.Type, Value: value})
+	}
+	return result, nil
+}
+
+func (n *nodeClient) MaxGas() (string, error) {
+	response, err := n.client.MaxGas()
+	if err != nil {
+		return "", err
+	}
+	return response.MaxGas, nil
+}
+
+func (n *nodeClient) MinGasPrice() (string, error) {
+	response, err := n.client.MinGasPrice()
+	if err != nil {
+		return "", err
+	}
+	return response.MinGasPrice, nil
+}
+
+func (n *nodeClient) MissedBlocksAtHeight(pubKey string, height int) (*api.MissedBlocksResult, error) {
+	response, err := n.client.MissedBlocks(pubKey, height)
+	if err != nil {
+		return nil, err
+	}
+	return &api.MissedBlocksResult{MissedBlocks: response.MissedBlocks, MissedBlocksCount: response.MissedBlocksCount}, nil
+}
+
+func (n *nodeClient) SendRawTransaction(tx string) (*api.SendTransactionResult, error) {
+	response, err := n.client.SendTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+	code, err := optionalAtoi(response.Code)
+	if err != nil {
+		return nil, err
+	}
+	return &api.SendTransactionResult{Code: code, Data: response.Data, Log: response.Log, Hash: response.Hash}, nil
+}
+
+func (n *nodeClient) Transaction(hash string) (*api.TransactionResult, error) {
+	response, err := n.client.Transaction(hash)
+	if err != nil {
+		return nil, err
+	}
+	return transactionResult(response)
+}
+
+func (n *nodeClient) Transactions(query string, page int, perPage int) ([]*api.TransactionResult, error) {
+	response, err := n.client.Transactions(query, page, perPage)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*api.TransactionResult, 0, len(response.Transactions))
+	for _, tx := range response.Transactions {
+		transaction, err := transactionResult(tx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, transaction)
+	}
+	return result, nil
+}
+
+func (n *nodeClient) UnconfirmedTxs(limit int) (*api.UnconfirmedTxsResult, error) {
+	response, err := n.client.UnconfirmedTxs(limit)
+	if err != nil {
+		return nil, err
+	}
+	return &api.UnconfirmedTxsResult{
+		NTxs:       response.TransactionsCount,
+		Total:      response.TotalTransactions,
+		TotalBytes: response.TotalBytes,
+		Txs:        response.Transactions,
+	}, nil
+}
+
+func (n *nodeClient) ValidatorsPage(height, page, perPage int) ([]*api.ValidatorResult, error) {
+	response, err := n.client.Validators(page, perPage, height)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*api.ValidatorResult, 0, len(response.Validators))
+	for _, validator := range response.Validators {
+		result = append(result, &api.ValidatorResult{PubKey: validator.PublicKey, VotingPower: validator.VotingPower})
+	}
+	return result, nil
+}
+
+func candidateResult(response *api_pb.CandidateResponse) (*api.CandidateResult, error) {
+	status, err := optionalAtoi(response.Status)
+	if err != nil {
+		return nil, err
+	}
+	result := &api.CandidateResult{
+		RewardAddress: response.RewardAddress,
+		TotalStake:    response.TotalStake,
+		PubKey:        response.PublicKey,
+		Commission:    response.Commission,
+		Status:        status,
+	}
+	for _, stake := range response.Stakes {
+		result.Stakes = append(result.Stakes, candidateStake{Owner: stake.Owner, Coin: stake.Coin, Value: stake.Value, BipValue: stake.BipValue})
+	}
+	return result, nil
+}
+
+func transactionResult(response *api_pb.TransactionResponse) (*api.TransactionResult, error) {
+	index, err := optionalAtoi(response.Index)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, err := optionalAtoi(response.GasPrice)
+	if err != nil {
+		return nil, err
+	}
+	txType, err := optionalAtoi(response.Type)
+	if err != nil {
+		return nil, err
+	}
+	code, err := optionalAtoi(response.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api.TransactionResult{
+		Hash:     response.Hash,
+		RawTx:    response.RawTx,
+		Height:   response.Height,
+		Index:    index,
+		From:     response.From,
+		Nonce:    response.Nonce,
+		Gas:      response.Gas,
+		GasPrice: gasPrice,
+		GasCoin:  response.GasCoin,
+		Type:     txType,
+		Payload:  response.Payload,
+		Code:     uint32(code),
+		Log:      response.Log,
+	}
+
+	if response.Data != nil {
+		data, err := structToJSON(response.Data)
+		if err != nil {
+			return nil, err
+		}
+		var dataMap map[string]interface{}
+		err = json.Unmarshal(data, &dataMap)
+		if err != nil {
+			return nil, err
+		}
+		result.Data = dataMap
+	}
+
+	if len(response.Tags) != 0 {
+		tags, err := json.Marshal(response.Tags)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(tags, &result.Tags)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func structToJSON(s *_struct.Struct) ([]byte, error) {
+	var buf bytes.Buffer
+	err := (&jsonpb.Marshaler{OrigName: true}).Marshal(&buf, s)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func structToStrings(s *_struct.Struct) (map[string]string, error) {
+	if s == nil {
+		return nil, nil
+	}
+	b, err := structToJSON(s)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	values := make(map[string]interface{})
+	err = decoder.Decode(&values)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		result[key] = fmt.Sprint(value)
+	}
+	return result, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+func optionalAtoi(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,144 @@
+package grpc_client
+
+import (
+	"context"
+	"github.com/MinterTeam/node-grpc-gateway/api_pb"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/nikolaev-dev/sdk/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"testing"
+)
+
+type nodeClientServer struct {
+	api_pb.UnimplementedApiServiceServer
+}
+
+func (s *nodeClientServer) Address(_ context.Context, req *api_pb.AddressRequest) (*api_pb.AddressResponse, error) {
+	return &api_pb.AddressResponse{Balance: map[string]string{"MNT": "1000"}, TransactionsCount: "7"}, nil
+}
+
+func (s *nodeClientServer) Block(_ context.Context, req *api_pb.BlockRequest) (*api_pb.BlockResponse, error) {
+	return &api_pb.BlockResponse{
+		Hash:              "ABCD",
+		Height:            "12",
+		Time:              "2020-04-09T11:27:24.530403396+03:00",
+		TransactionsCount: "1",
+		Transactions: []*api_pb.BlockResponse_Transaction{{
+			Hash:     "Mt01",
+			From:     "Mxeeee1973381ab793719fff497b9a516719fcd5a2",
+			Nonce:    "8",
+			GasPrice: "1",
+			Type:     "1",
+			Data: &_struct.Struct{Fields: map[string]*_struct.Value{
+				"coin":  {Kind: &_struct.Value_StringValue{StringValue: "MNT"}},
+				"to":    {Kind: &_struct.Value_StringValue{StringValue: "Mxee81347211c72524338f9680072af90744333146"}},
+				"value": {Kind: &_struct.Value_StringValue{StringValue: "1000000000000000000"}},
+			}},
+			Tags: map[string]string{"tx.from": "eeee1973381ab793719fff497b9a516719fcd5a2", "tx.type": "01"},
+			Code: "0",
+		}},
+		Validators: []*api_pb.BlockResponse_Validator{{PublicKey: "Mp01", Signed: true}},
+	}, nil
+}
+
+func (s *nodeClientServer) Events(_ context.Context, req *api_pb.EventsRequest) (*api_pb.EventsResponse, error) {
+	return &api_pb.EventsResponse{Events: []*api_pb.EventsResponse_Event{{
+		Type: "minter/RewardEvent",
+		Value: &_struct.Struct{Fields: map[string]*_struct.Value{
+			"role":   {Kind: &_struct.Value_StringValue{StringValue: "DAO"}},
+			"amount": {Kind: &_struct.Value_StringValue{StringValue: "367300000000000000000"}},
+		}},
+	}}}, nil
+}
+
+func newNodeClient(t *testing.T) api.NodeClient {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	api_pb.RegisterApiServiceServer(server, &nodeClientServer{})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	client, err := NewNodeClient("bufnet", WithDialOptions(grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return listener.Dial()
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestNodeClient_Address(t *testing.T) {
+	client := newNodeClient(t)
+
+	address, err := client.AddressAtHeight("Mxeeee1973381ab793719fff497b9a516719fcd5a2", api.LatestBlockHeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address.Balance["MNT"] != "1000" || address.TransactionCount != "7" {
+		t.Errorf("address got %+v", address)
+	}
+
+	nonce, err := client.Nonce("Mxeeee1973381ab793719fff497b9a516719fcd5a2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nonce != 8 {
+		t.Errorf("nonce got %d, want %d", nonce, 8)
+	}
+}
+
+func TestNodeClient_Block(t *testing.T) {
+	client := newNodeClient(t)
+
+	block, err := client.Block(12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.Height != "12" || block.Time.Unix() != 1586420844 || len(block.Validators) != 1 || !block.Validators[0].Signed || block.TotalTxs != "" {
+		t.Errorf("block got %+v", block)
+	}
+	if len(block.Transactions) != 1 {
+		t.Fatalf("transactions count got %d, want %d", len(block.Transactions), 1)
+	}
+
+	tx := block.Transactions[0]
+	if tx.Type != 1 || tx.GasPrice != 1 || tx.Height != "12" || tx.Tags.TxFrom != "eeee1973381ab793719fff497b9a516719fcd5a2" {
+		t.Errorf("transaction got %+v", tx)
+	}
+	data, err := tx.DataStruct()
+	if err != nil {
+		t.Fatal(err)
+	}
+	send, ok := data.(*api.SendData)
+	if !ok {
+		t.Fatalf("interface conversion: interface {} is %T", data)
+	}
+	if send.Value != "1000000000000000000" || send.Coin != "MNT" {
+		t.Errorf("send data got %+v", send)
+	}
+}
+
+func TestNodeClient_Events(t *testing.T) {
+	client := newNodeClient(t)
+
+	events, err := client.EventsAtHeight(12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events.Events) != 1 {
+		t.Fatalf("events count got %d, want %d", len(events.Events), 1)
+	}
+	value, err := events.Events[0].ValueStruct()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reward, ok := value.(*api.RewardEventValue)
+	if !ok {
+		t.Fatalf("interface conversion: interface {} is %T", value)
+	}
+	if reward.Role != "DAO" || reward.Amount != "367300000000000000000" {
+		t.Errorf("reward got %+v", reward)
+	}
+}
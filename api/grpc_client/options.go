@@ -0,0 +1,99 @@
+package grpc_client
+
+import (
+	"context"
+	"crypto/tls"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"time"
+)
+
+// Option configures the client created by NewWithOptions.
+type Option func(*options)
+
+type options struct {
+	credentials        credentials.TransportCredentials
+	timeout            time.Duration
+	unaryInterceptors  []grpc.UnaryClientInterceptor
+	streamInterceptors []grpc.StreamClientInterceptor
+	dialOptions        []grpc.DialOption
+}
+
+// Use transport credentials instead of insecure connection.
+func WithTransportCredentials(creds credentials.TransportCredentials) Option {
+	return func(o *options) {
+		o.credentials = creds
+	}
+}
+
+// Use TLS connection with the config, nil config uses system root CAs.
+func WithTLS(config *tls.Config) Option {
+	if config == nil {
+		config = &tls.Config{}
+	}
+	return WithTransportCredentials(credentials.NewTLS(config))
+}
+
+// Set timeout of every unary call. Subscriptions are not limited by timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// Set keepalive parameters of the connection.
+func WithKeepalive(params keepalive.ClientParameters) Option {
+	return WithDialOptions(grpc.WithKeepaliveParams(params))
+}
+
+// Add unary interceptors, they are called in the order of addition.
+func WithUnaryInterceptors(interceptors ...grpc.UnaryClientInterceptor) Option {
+	return func(o *options) {
+		o.unaryInterceptors = append(o.unaryInterceptors, interceptors...)
+	}
+}
+
+// Add stream interceptors, they are called in the order of addition.
+func WithStreamInterceptors(interceptors ...grpc.StreamClientInterceptor) Option {
+	return func(o *options) {
+		o.streamInterceptors = append(o.streamInterceptors, interceptors...)
+	}
+}
+
+// Add custom dial options.
+func WithDialOptions(dialOptions ...grpc.DialOption) Option {
+	return func(o *options) {
+		o.dialOptions = append(o.dialOptions, dialOptions...)
+	}
+}
+
+func (o *options) grpcDialOptions() []grpc.DialOption {
+	dialOptions := make([]grpc.DialOption, 0, len(o.dialOptions)+3)
+	if o.credentials != nil {
+		dialOptions = append(dialOptions, grpc.WithTransportCredentials(o.credentials))
+	} else {
+		dialOptions = append(dialOptions, grpc.WithInsecure())
+	}
+
+	unaryInterceptors := o.unaryInterceptors
+	if o.timeout > 0 {
+		unaryInterceptors = append([]grpc.UnaryClientInterceptor{timeoutInterceptor(o.timeout)}, unaryInterceptors...)
+	}
+	if len(unaryInterceptors) != 0 {
+		dialOptions = append(dialOptions, grpc.WithChainUnaryInterceptor(unaryInterceptors...))
+	}
+	if len(o.streamInterceptors) != 0 {
+		dialOptions = append(dialOptions, grpc.WithChainStreamInterceptor(o.streamInterceptors...))
+	}
+
+	return append(dialOptions, o.dialOptions...)
+}
+
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,90 @@
+package grpc_client
+
+import (
+	"context"
+	"github.com/MinterTeam/node-grpc-gateway/api_pb"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"testing"
+	"time"
+)
+
+type slowStatusServer struct {
+	api_pb.UnimplementedApiServiceServer
+	delay time.Duration
+}
+
+func (s *slowStatusServer) Status(ctx context.Context, _ *empty.Empty) (*api_pb.StatusResponse, error) {
+	select {
+	case <-time.After(s.delay):
+		return &api_pb.StatusResponse{Version: "test"}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func newSlowServer(t *testing.T, delay time.Duration) func(context.Context, string) (net.Conn, error) {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	api_pb.RegisterApiServiceServer(server, &slowStatusServer{delay: delay})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return func(context.Context, string) (net.Conn, error) {
+		return listener.Dial()
+	}
+}
+
+func TestNewWithOptions_timeout(t *testing.T) {
+	dialer := newSlowServer(t, 100*time.Millisecond)
+
+	var intercepted []string
+	client, err := NewWithOptions("bufnet",
+		WithTimeout(10*time.Millisecond),
+		WithUnaryInterceptors(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			intercepted = append(intercepted, method)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}),
+		WithDialOptions(grpc.WithContextDialer(dialer)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	_, err = client.Status()
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("error got %v, want %s", err, codes.DeadlineExceeded)
+	}
+	if len(intercepted) != 1 || intercepted[0] != "/api_pb.ApiService/Status" {
+		t.Errorf("intercepted got %v, want %v", intercepted, []string{"/api_pb.ApiService/Status"})
+	}
+}
+
+func TestClient_Close(t *testing.T) {
+	dialer := newSlowServer(t, 0)
+
+	client, err := NewWithOptions("bufnet", WithDialOptions(grpc.WithContextDialer(dialer)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := client.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Version != "test" {
+		t.Errorf("version got %s, want %s", response.Version, "test")
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Status()
+	if status.Code(err) != codes.Canceled {
+		t.Errorf("error got %v, want %s", err, codes.Canceled)
+	}
+}
@@ -0,0 +1,415 @@
+package grpc_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/MinterTeam/node-grpc-gateway/api_pb"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/nikolaev-dev/sdk/api"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decoded event of subscription. Block is set for "tm.event='NewBlock'" subscriptions, Transaction is set otherwise.
+type SubscriptionEvent struct {
+	Height      int
+	Block       *api.BlockResult
+	Transaction *api.TransactionResult
+	// Event was fetched after reconnection to fill the gap of missed heights.
+	Backfilled bool
+}
+
+// SubscriptionOption configures the subscription created by SubscribeEvents.
+type SubscriptionOption func(*subscriptionOptions)
+
+type subscriptionOptions struct {
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	buffer     int
+	perPage    int
+	onError    func(error)
+}
+
+// Least backoff of reconnections, so a broken node is not requested in a tight loop.
+const minBackoffFloor = 100 * time.Millisecond
+
+// Set exponential backoff of reconnections, 1s and 30s by default.
+// Min is raised to 100ms if it is not positive, max is raised to min if it is less.
+func WithBackoff(min, max time.Duration) SubscriptionOption {
+	if min <= 0 {
+		min = minBackoffFloor
+	}
+	if max < min {
+		max = min
+	}
+	return func(o *subscriptionOptions) {
+		o.minBackoff, o.maxBackoff = min, max
+	}
+}
+
+// Set events channel buffer size, 100 by default.
+func WithBuffer(size int) SubscriptionOption {
+	return func(o *subscriptionOptions) {
+		o.buffer = size
+	}
+}
+
+// Set handler of transient errors which caused reconnection.
+func WithErrorHandler(f func(error)) SubscriptionOption {
+	return func(o *subscriptionOptions) {
+		o.onError = f
+	}
+}
+
+// Error of transactions subscription with the query which cannot be narrowed to the missed heights for back-filling,
+// e.g. a query with OR.
+var ErrUnsupportedQuery = errors.New("query cannot be back-filled")
+
+// Typed subscription with reconnection and gap back-filling.
+type Subscription struct {
+	client  *Client
+	node    api.NodeClient
+	query   string
+	blocks  bool
+	options *subscriptionOptions
+	events  chan *SubscriptionEvent
+	err     error
+
+	// last delivered height and hashes of transactions delivered at it
+	lastHeight int
+	lastHashes map[string]bool
+}
+
+// Subscribe to events by query and receive them decoded on the channel.
+// The subscription reconnects with backoff when the stream is broken and back-fills events of the heights missed
+// while it was disconnected: blocks via Block and transactions via Transactions with the query narrowed to the heights.
+// The channel is closed when the context is done. Transactions queries are conditions joined with AND,
+// otherwise the channel is closed at once with ErrUnsupportedQuery.
+func (c *Client) SubscribeEvents(ctx context.Context, query string, opts ...SubscriptionOption) *Subscription {
+	o := &subscriptionOptions{minBackoff: time.Second, maxBackoff: 30 * time.Second, buffer: 100, perPage: 100}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	s := &Subscription{
+		client:  c,
+		node:    c.NodeClient(),
+		query:   query,
+		blocks:  newBlockQuery.MatchString(query),
+		options: o,
+		events:  make(chan *SubscriptionEvent, o.buffer),
+	}
+	if !s.blocks && !splittableQuery(query) {
+		s.err = fmt.Errorf("%w: %q", ErrUnsupportedQuery, query)
+		close(s.events)
+		return s
+	}
+	go s.run(ctx)
+	return s
+}
+
+// Returns channel of events.
+func (s *Subscription) Events() <-chan *SubscriptionEvent {
+	return s.events
+}
+
+// Returns the reason of subscription end, valid after the events channel is closed.
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+var (
+	newBlockQuery = regexp.MustCompile(`tm\.event\s*=\s*'NewBlock'`)
+	txEventQuery  = regexp.MustCompile(`^tm\.event\s*=\s*'Tx'$`)
+	queryAnd      = regexp.MustCompile(`\s+AND\s+`)
+	queryOr       = regexp.MustCompile(`\s+OR\s+|[()]`)
+	queryValue    = regexp.MustCompile(`'[^']*'`)
+)
+
+// Query is split on AND by back-filling, OR and grouping would change its meaning.
+func splittableQuery(query string) bool {
+	return !queryOr.MatchString(queryValue.ReplaceAllString(query, "''"))
+}
+
+func (s *Subscription) run(ctx context.Context) {
+	defer close(s.events)
+
+	retry := &backoff{min: s.options.minBackoff, max: s.options.maxBackoff}
+	for {
+		err := s.session(ctx)
+		if ctx.Err() != nil {
+			s.err = ctx.Err()
+			return
+		}
+		if err == errSessionReceived {
+			retry.reset()
+		} else if err != nil && s.options.onError != nil {
+			s.options.onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.err = ctx.Err()
+			return
+		case <-time.After(retry.next()):
+		}
+	}
+}
+
+// Exponential backoff starting from min, reset returns it to min.
+type backoff struct {
+	min, max time.Duration
+	current  time.Duration
+}
+
+// Returns the delay before the next reconnection and doubles the following one.
+func (b *backoff) next() time.Duration {
+	delay := b.current
+	if delay == 0 {
+		delay = b.min
+	}
+	b.current = delay * 2
+	if b.current > b.max {
+		b.current = b.max
+	}
+	return delay
+}
+
+func (b *backoff) reset() {
+	b.current = 0
+}
+
+var errSessionReceived = errors.New("stream is broken after events were received")
+
+// One connection of the subscription. Returns errSessionReceived if at least one event was received.
+func (s *Subscription) session(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := s.client.grpcClient.Subscribe(ctx, &api_pb.SubscribeRequest{Query: s.query})
+	if err != nil {
+		return err
+	}
+
+	skipUpTo := 0
+	if s.lastHeight != 0 {
+		status, err := s.node.Status()
+		if err != nil {
+			return err
+		}
+		head, err := strconv.Atoi(status.LatestBlockHeight)
+		if err != nil {
+			return err
+		}
+		err = s.backfill(ctx, head)
+		if err != nil {
+			return err
+		}
+		skipUpTo = head
+	}
+
+	received := false
+	for {
+		response, err := stream.Recv()
+		if err != nil {
+			if received {
+				if s.options.onError != nil {
+					s.options.onError(err)
+				}
+				return errSessionReceived
+			}
+			return err
+		}
+		received = true
+
+		event, height, err := s.decode(response, skipUpTo)
+		if err != nil {
+			if s.lastHeight == 0 && height != 0 {
+				// back-fill the failed event after reconnection
+				s.lastHeight, s.lastHashes = height, make(map[string]bool)
+				if s.blocks {
+					s.lastHeight--
+				}
+			}
+			return err
+		}
+		if event == nil {
+			continue
+		}
+		if !s.deliver(ctx, event) {
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Subscription) decode(response *api_pb.SubscribeResponse, skipUpTo int) (*SubscriptionEvent, int, error) {
+	if s.blocks {
+		height, err := blockEventHeight(response)
+		if err != nil {
+			return nil, 0, err
+		}
+		if height <= skipUpTo || height <= s.lastHeight {
+			return nil, height, nil
+		}
+		block, err := s.node.Block(height)
+		if err != nil {
+			return nil, height, err
+		}
+		return &SubscriptionEvent{Height: height, Block: block}, height, nil
+	}
+
+	hash, height, err := txEventHashHeight(response)
+	if err != nil {
+		return nil, 0, err
+	}
+	if height <= skipUpTo || height < s.lastHeight || (height == s.lastHeight && s.lastHashes[hash]) {
+		return nil, height, nil
+	}
+	transaction, err := s.node.Transaction(hash)
+	if err != nil {
+		return nil, height, err
+	}
+	return &SubscriptionEvent{Height: height, Transaction: transaction}, height, nil
+}
+
+// Deliver events of heights from the last delivered to head.
+func (s *Subscription) backfill(ctx context.Context, head int) error {
+	if s.blocks {
+		for height := s.lastHeight + 1; height <= head; height++ {
+			block, err := s.node.Block(height)
+			if err != nil {
+				return err
+			}
+			if !s.deliver(ctx, &SubscriptionEvent{Height: height, Block: block, Backfilled: true}) {
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
+
+	if head < s.lastHeight {
+		return nil
+	}
+	// tm.event is not indexed by the transactions search
+	var conditions []string
+	for _, condition := range queryAnd.Split(strings.TrimSpace(s.query), -1) {
+		if condition != "" && !txEventQuery.MatchString(condition) {
+			conditions = append(conditions, condition)
+		}
+	}
+	conditions = append(conditions, fmt.Sprintf("tx.height>=%d AND tx.height<=%d", s.lastHeight, head))
+	query := strings.Join(conditions, " AND ")
+
+	var transactions []*api.TransactionResult
+	for page := 1; ; page++ {
+		result, err := s.node.Transactions(query, page, s.options.perPage)
+		if err != nil {
+			return err
+		}
+		transactions = append(transactions, result...)
+		if len(result) < s.options.perPage {
+			break
+		}
+	}
+
+	heights := make(map[*api.TransactionResult]int, len(transactions))
+	for _, tx := range transactions {
+		height, err := strconv.Atoi(tx.Height)
+		if err != nil {
+			return err
+		}
+		heights[tx] = height
+	}
+	sort.SliceStable(transactions, func(i, j int) bool {
+		if heights[transactions[i]] != heights[transactions[j]] {
+			return heights[transactions[i]] < heights[transactions[j]]
+		}
+		return transactions[i].Index < transactions[j].Index
+	})
+
+	for _, tx := range transactions {
+		height := heights[tx]
+		if height == s.lastHeight && s.lastHashes[tx.Hash] {
+			continue
+		}
+		if !s.deliver(ctx, &SubscriptionEvent{Height: height, Transaction: tx, Backfilled: true}) {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (s *Subscription) deliver(ctx context.Context, event *SubscriptionEvent) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case s.events <- event:
+	}
+
+	if event.Height != s.lastHeight {
+		s.lastHeight = event.Height
+		s.lastHashes = make(map[string]bool)
+	}
+	if event.Transaction != nil {
+		s.lastHashes[event.Transaction.Hash] = true
+	}
+	return true
+}
+
+func txEventHashHeight(response *api_pb.SubscribeResponse) (string, int, error) {
+	var hash, height string
+	for _, event := range response.Events {
+		if len(event.Events) == 0 {
+			continue
+		}
+		switch event.Key {
+		case "tx.hash":
+			hash = "Mt" + strings.ToLower(event.Events[0])
+		case "tx.height":
+			height = event.Events[0]
+		}
+	}
+	if hash == "" || height == "" {
+		return "", 0, errors.New("subscription event has no tx.hash or tx.height")
+	}
+	h, err := strconv.Atoi(height)
+	if err != nil {
+		return "", 0, err
+	}
+	return hash, h, nil
+}
+
+func blockEventHeight(response *api_pb.SubscribeResponse) (int, error) {
+	value := structPath(response.Data, "block", "header", "height")
+	if value == nil {
+		value = structPath(response.Data, "height")
+	}
+	switch v := value.GetKind().(type) {
+	case *_struct.Value_StringValue:
+		return strconv.Atoi(v.StringValue)
+	case *_struct.Value_NumberValue:
+		return int(v.NumberValue), nil
+	}
+	return 0, errors.New("subscription event has no block height")
+}
+
+func structPath(s *_struct.Struct, path ...string) *_struct.Value {
+	for i, key := range path {
+		if s == nil {
+			return nil
+		}
+		value, ok := s.Fields[key]
+		if !ok {
+			return nil
+		}
+		if i == len(path)-1 {
+			return value
+		}
+		s = value.GetStructValue()
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package grpc_client
+
+import (
+	"context"
+	"errors"
+	"github.com/MinterTeam/node-grpc-gateway/api_pb"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type subscriptionServer struct {
+	api_pb.UnimplementedApiServiceServer
+
+	mu       sync.Mutex
+	sessions int
+	queries  []string
+}
+
+func txEvent(hash string, height int) *api_pb.SubscribeResponse {
+	return &api_pb.SubscribeResponse{Events: []*api_pb.SubscribeResponse_Event{
+		{Key: "tm.event", Events: []string{"Tx"}},
+		{Key: "tx.hash", Events: []string{strings.ToUpper(hash)}},
+		{Key: "tx.height", Events: []string{strconv.Itoa(height)}},
+	}}
+}
+
+func (s *subscriptionServer) Subscribe(req *api_pb.SubscribeRequest, stream api_pb.ApiService_SubscribeServer) error {
+	s.mu.Lock()
+	s.sessions++
+	session := s.sessions
+	s.mu.Unlock()
+
+	if session == 1 {
+		if err := stream.Send(txEvent("aa", 10)); err != nil {
+			return err
+		}
+		return errors.New("connection reset")
+	}
+	if err := stream.Send(txEvent("cc", 12)); err != nil {
+		return err
+	}
+	if err := stream.Send(txEvent("dd", 13)); err != nil {
+		return err
+	}
+	<-stream.Context().Done()
+	return nil
+}
+
+func (s *subscriptionServer) Status(context.Context, *empty.Empty) (*api_pb.StatusResponse, error) {
+	return &api_pb.StatusResponse{LatestBlockHeight: "12"}, nil
+}
+
+func (s *subscriptionServer) Transaction(_ context.Context, req *api_pb.TransactionRequest) (*api_pb.TransactionResponse, error) {
+	height := map[string]string{"Mtaa": "10", "Mtdd": "13"}[req.Hash]
+	return &api_pb.TransactionResponse{Hash: req.Hash, Height: height, Type: "1"}, nil
+}
+
+func (s *subscriptionServer) Transactions(_ context.Context, req *api_pb.TransactionsRequest) (*api_pb.TransactionsResponse, error) {
+	s.mu.Lock()
+	s.queries = append(s.queries, req.Query)
+	s.mu.Unlock()
+	return &api_pb.TransactionsResponse{Transactions: []*api_pb.TransactionResponse{
+		{Hash: "Mtcc", Height: "12", Type: "1"},
+		{Hash: "Mtbb", Height: "11", Type: "1"},
+		{Hash: "Mtaa", Height: "10", Type: "1"},
+	}}, nil
+}
+
+func TestClient_SubscribeEvents(t *testing.T) {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	fake := &subscriptionServer{}
+	api_pb.RegisterApiServiceServer(server, fake)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	client, err := NewWithOptions("bufnet", WithDialOptions(grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return listener.Dial()
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	subscription := client.SubscribeEvents(ctx, "tm.event='Tx' AND tags.tx.type='01'", WithBackoff(time.Millisecond, time.Millisecond))
+
+	want := []struct {
+		hash       string
+		height     int
+		backfilled bool
+	}{
+		{"Mtaa", 10, false},
+		{"Mtbb", 11, true},
+		{"Mtcc", 12, true},
+		{"Mtdd", 13, false},
+	}
+	for _, w := range want {
+		event, ok := <-subscription.Events()
+		if !ok {
+			t.Fatalf("events channel is closed: %v", subscription.Err())
+		}
+		if event.Transaction.Hash != w.hash || event.Height != w.height || event.Backfilled != w.backfilled {
+			t.Errorf("event got %s at %d (backfilled %t), want %s at %d (backfilled %t)",
+				event.Transaction.Hash, event.Height, event.Backfilled, w.hash, w.height, w.backfilled)
+		}
+	}
+
+	cancel()
+	for range subscription.Events() {
+	}
+	if subscription.Err() != context.Canceled {
+		t.Errorf("error got %v, want %v", subscription.Err(), context.Canceled)
+	}
+
+	wantQuery := "tags.tx.type='01' AND tx.height>=10 AND tx.height<=12"
+	if len(fake.queries) != 1 || fake.queries[0] != wantQuery {
+		t.Errorf("backfill queries got %v, want %v", fake.queries, []string{wantQuery})
+	}
+}
+
+func TestClient_SubscribeEvents_unsupportedQuery(t *testing.T) {
+	client := &Client{}
+	for query, supported := range map[string]bool{
+		"tm.event='Tx' AND tags.tx.type='01'":                      true,
+		"tm.event='Tx' AND tags.tx.coin='A OR B'":                  true,
+		"tm.event='NewBlock'":                                      true,
+		"tm.event='Tx' AND tags.tx.from='aa' OR tags.tx.to='aa'":   false,
+		"tm.event='Tx' AND (tags.tx.from='aa' OR tags.tx.to='aa')": false,
+	} {
+		if splittableQuery(query) != supported {
+			t.Errorf("query %q supported got %t, want %t", query, !supported, supported)
+		}
+		if supported {
+			continue
+		}
+		subscription := client.SubscribeEvents(context.Background(), query)
+		if _, ok := <-subscription.Events(); ok || !errors.Is(subscription.Err(), ErrUnsupportedQuery) {
+			t.Errorf("query %q error got %v, want ErrUnsupportedQuery", query, subscription.Err())
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	b := &backoff{min: time.Second, max: 5 * time.Second}
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if delay := b.next(); delay != want {
+			t.Errorf("delay #%d got %s, want %s", i, delay, want)
+		}
+	}
+	b.reset()
+	if delay := b.next(); delay != time.Second {
+		t.Errorf("delay after reset got %s, want %s", delay, time.Second)
+	}
+	if delay := b.next(); delay != 2*time.Second {
+		t.Errorf("second delay after reset got %s, want %s", delay, 2*time.Second)
+	}
+}
+
+func TestWithBackoff(t *testing.T) {
+	for _, tt := range []struct {
+		min, max         time.Duration
+		wantMin, wantMax time.Duration
+	}{
+		{time.Second, time.Minute, time.Second, time.Minute},
+		{0, 0, minBackoffFloor, minBackoffFloor},
+		{-time.Second, time.Second, minBackoffFloor, time.Second},
+		{time.Second, time.Millisecond, time.Second, time.Second},
+	} {
+		o := new(subscriptionOptions)
+		WithBackoff(tt.min, tt.max)(o)
+		if o.minBackoff != tt.wantMin || o.maxBackoff != tt.wantMax {
+			t.Errorf("WithBackoff(%s, %s) got %s, %s, want %s, %s", tt.min, tt.max, o.minBackoff, o.maxBackoff, tt.wantMin, tt.wantMax)
+		}
+	}
+}
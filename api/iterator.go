@@ -0,0 +1,307 @@
+package api
+
+import (
+	"context"
+	"strings"
+)
+
+// Default and maximal page size of list endpoints.
+const DefaultPerPage = 100
+
+// Node responds with error to request of page after the last one.
+func isPageOutOfRange(err error) bool {
+	return strings.Contains(err.Error(), "page should be within")
+}
+
+// IteratorOption configures iterators of list endpoints.
+type IteratorOption func(*iteratorOptions)
+
+type iteratorOptions struct {
+	prefetch bool
+}
+
+// Request the next page concurrently while the current one is iterated.
+func WithPrefetch() IteratorOption {
+	return func(o *iteratorOptions) {
+		o.prefetch = true
+	}
+}
+
+func newIteratorOptions(opts []IteratorOption) *iteratorOptions {
+	o := &iteratorOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+type fetchedPage struct {
+	items interface{}
+	count int
+	err   error
+}
+
+// Lazy sequence of pages. A page shorter than perPage or response "page should be within" to page > 1 ends it.
+type pager struct {
+	fetch    func(page int) (items interface{}, count int, err error)
+	perPage  int
+	prefetch bool
+
+	page    int
+	last    bool
+	pending chan fetchedPage
+}
+
+func (p *pager) request(page int) chan fetchedPage {
+	result := make(chan fetchedPage, 1)
+	request := func() {
+		items, count, err := p.fetch(page)
+		result <- fetchedPage{items: items, count: count, err: err}
+	}
+	if p.prefetch {
+		go request()
+	} else {
+		request()
+	}
+	return result
+}
+
+// Returns items of the next page, nil items at the end.
+func (p *pager) next(ctx context.Context) (interface{}, error) {
+	for !p.last {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		pending := p.pending
+		p.pending = nil
+		if pending == nil {
+			pending = p.request(p.page + 1)
+		}
+
+		var fetched fetchedPage
+		select {
+		case <-ctx.Done():
+			// keep the request to wait for it on the next call
+			p.pending = pending
+			return nil, ctx.Err()
+		case fetched = <-pending:
+		}
+		p.page++
+
+		if fetched.err != nil {
+			if p.page > 1 && isPageOutOfRange(fetched.err) {
+				p.last = true
+				return nil, nil
+			}
+			p.page--
+			return nil, fetched.err
+		}
+		p.last = fetched.count < p.perPage
+		if !p.last && p.prefetch {
+			p.pending = p.request(p.page + 1)
+		}
+		if fetched.count != 0 {
+			return fetched.items, nil
+		}
+	}
+	return nil, nil
+}
+
+// Iterator of transactions matching query, pages are requested lazily.
+//
+//	it := api.NewTransactionsIterator(minterClient, query, 0)
+//	for it.Next(ctx) {
+//		tx := it.Transaction()
+//	}
+//	if err := it.Err(); err != nil {...}
+type TransactionsIterator struct {
+	node    NodeClient
+	query   *TransactionsQuery
+	perPage int
+	options *iteratorOptions
+
+	queries []string
+	index   int
+	pager   *pager
+	buffer  []*TransactionResult
+	current *TransactionResult
+	// hashes of returned transactions, to merge results of query alternatives
+	seen map[string]bool
+	err  error
+}
+
+// Create iterator of transactions matching query, perPage 0 means DefaultPerPage.
+// Transactions matching several alternatives of query are returned once.
+func NewTransactionsIterator(node NodeClient, query *TransactionsQuery, perPage int, opts ...IteratorOption) *TransactionsIterator {
+	if perPage <= 0 {
+		perPage = DefaultPerPage
+	}
+	return &TransactionsIterator{node: node, query: query, perPage: perPage, options: newIteratorOptions(opts)}
+}
+
+// Advance to the next transaction, returns false at the end or on error.
+func (it *TransactionsIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if it.queries == nil {
+		it.queries, it.err = it.query.Queries()
+		if it.err != nil {
+			return false
+		}
+		if len(it.queries) > 1 {
+			it.seen = make(map[string]bool)
+		}
+	}
+
+	for {
+		for len(it.buffer) != 0 {
+			it.current, it.buffer = it.buffer[0], it.buffer[1:]
+			if it.seen == nil {
+				return true
+			}
+			if !it.seen[it.current.Hash] {
+				it.seen[it.current.Hash] = true
+				return true
+			}
+		}
+		it.current = nil
+
+		if it.index >= len(it.queries) {
+			return false
+		}
+		if it.pager == nil {
+			query := it.queries[it.index]
+			it.pager = &pager{
+				perPage:  it.perPage,
+				prefetch: it.options.prefetch,
+				fetch: func(page int) (interface{}, int, error) {
+					transactions, err := it.node.Transactions(query, page, it.perPage)
+					return transactions, len(transactions), err
+				},
+			}
+		}
+
+		items, err := it.pager.next(ctx)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		if items == nil {
+			it.index++
+			it.pager = nil
+			continue
+		}
+		it.buffer = items.([]*TransactionResult)
+	}
+}
+
+// Returns current transaction.
+func (it *TransactionsIterator) Transaction() *TransactionResult {
+	return it.current
+}
+
+// Returns error which stopped iteration.
+func (it *TransactionsIterator) Err() error {
+	return it.err
+}
+
+// Iterator of validators at height, pages are requested lazily.
+type ValidatorsIterator struct {
+	pager   *pager
+	buffer  []*ValidatorResult
+	current *ValidatorResult
+	err     error
+}
+
+// Create iterator of validators at height, 0 means the latest block, perPage 0 means DefaultPerPage.
+func NewValidatorsIterator(node NodeClient, height, perPage int, opts ...IteratorOption) *ValidatorsIterator {
+	if perPage <= 0 {
+		perPage = DefaultPerPage
+	}
+	return &ValidatorsIterator{pager: &pager{
+		perPage:  perPage,
+		prefetch: newIteratorOptions(opts).prefetch,
+		fetch: func(page int) (interface{}, int, error) {
+			validators, err := node.ValidatorsPage(height, page, perPage)
+			return validators, len(validators), err
+		},
+	}}
+}
+
+// Advance to the next validator, returns false at the end or on error.
+func (it *ValidatorsIterator) Next(ctx context.Context) bool {
+	for len(it.buffer) == 0 {
+		it.current = nil
+		if it.err != nil {
+			return false
+		}
+		items, err := it.pager.next(ctx)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		if items == nil {
+			return false
+		}
+		it.buffer = items.([]*ValidatorResult)
+	}
+	it.current, it.buffer = it.buffer[0], it.buffer[1:]
+	return true
+}
+
+// Returns current validator.
+func (it *ValidatorsIterator) Validator() *ValidatorResult {
+	return it.current
+}
+
+// Returns error which stopped iteration.
+func (it *ValidatorsIterator) Err() error {
+	return it.err
+}
+
+// Iterator of candidates at height. The node returns all candidates in one response, it is requested on the first Next.
+type CandidatesIterator struct {
+	fetch   func() ([]*CandidateResult, error)
+	fetched bool
+	buffer  []*CandidateResult
+	current *CandidateResult
+	err     error
+}
+
+// Create iterator of candidates at height, 0 means the latest block.
+func NewCandidatesIterator(node NodeClient, height int, includeStakes bool) *CandidatesIterator {
+	return &CandidatesIterator{fetch: func() ([]*CandidateResult, error) {
+		return node.CandidatesAtHeight(height, includeStakes)
+	}}
+}
+
+// Advance to the next candidate, returns false at the end or on error.
+func (it *CandidatesIterator) Next(ctx context.Context) bool {
+	if !it.fetched && it.err == nil {
+		if it.err = ctx.Err(); it.err != nil {
+			return false
+		}
+		it.buffer, it.err = it.fetch()
+		if it.err != nil {
+			return false
+		}
+		it.fetched = true
+	}
+	if len(it.buffer) == 0 {
+		it.current = nil
+		return false
+	}
+	it.current, it.buffer = it.buffer[0], it.buffer[1:]
+	return true
+}
+
+// Returns current candidate.
+func (it *CandidatesIterator) Candidate() *CandidateResult {
+	return it.current
+}
+
+// Returns error which stopped iteration.
+func (it *CandidatesIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,253 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Node with transactions search by exact query string, it responds like Tendermint to page out of range.
+type transactionsNode struct {
+	NodeClient
+	transactions map[string][]*TransactionResult
+	requests     int
+}
+
+func (n *transactionsNode) Transactions(query string, page int, perPage int) ([]*TransactionResult, error) {
+	n.requests++
+	all := n.transactions[query]
+	pages := (len(all) + perPage - 1) / perPage
+	if pages == 0 {
+		pages = 1
+	}
+	if page < 1 || page > pages {
+		return nil, &Error{Code: 500, Message: fmt.Sprintf("page should be within [1, %d] range, given %d", pages, page)}
+	}
+	from := (page - 1) * perPage
+	to := from + perPage
+	if to > len(all) {
+		to = len(all)
+	}
+	return all[from:to], nil
+}
+
+func transactionResults(prefix string, n int) []*TransactionResult {
+	result := make([]*TransactionResult, n)
+	for i := range result {
+		result[i] = &TransactionResult{Hash: prefix + strconv.Itoa(i)}
+	}
+	return result
+}
+
+func collectTransactions(t *testing.T, it *TransactionsIterator) []string {
+	var hashes []string
+	for it.Next(context.Background()) {
+		hashes = append(hashes, it.Transaction().Hash)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return hashes
+}
+
+func TestTransactionsIterator(t *testing.T) {
+	for _, n := range []int{0, 1, 9, 10, 25} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			node := &transactionsNode{transactions: map[string][]*TransactionResult{
+				"tags.tx.type='01'": transactionResults("Mt", n),
+			}}
+			hashes := collectTransactions(t, NewTransactionsIterator(node, NewTransactionsQuery().Type(1), 10))
+			if len(hashes) != n {
+				t.Fatalf("transactions want %d, got %d", n, len(hashes))
+			}
+			for i, hash := range hashes {
+				if hash != "Mt"+strconv.Itoa(i) {
+					t.Fatalf("unexpected order %v", hashes)
+				}
+			}
+			if want := n/10 + 1; node.requests != want {
+				t.Errorf("requests want %d, got %d", want, node.requests)
+			}
+		})
+	}
+}
+
+func TestTransactionsIterator_or(t *testing.T) {
+	shared := &TransactionResult{Hash: "Mtshared"}
+	node := &transactionsNode{transactions: map[string][]*TransactionResult{
+		"tags.tx.type='01'": append(transactionResults("Mta", 3), shared),
+		"tags.tx.type='02'": append([]*TransactionResult{shared}, transactionResults("Mtb", 2)...),
+	}}
+	query := NewTransactionsQuery().Type(1).Or(NewTransactionsQuery().Type(2))
+	hashes := collectTransactions(t, NewTransactionsIterator(node, query, 2))
+	want := []string{"Mta0", "Mta1", "Mta2", "Mtshared", "Mtb0", "Mtb1"}
+	if fmt.Sprint(hashes) != fmt.Sprint(want) {
+		t.Errorf("transactions want %v, got %v", want, hashes)
+	}
+}
+
+type failingNode struct {
+	NodeClient
+}
+
+func (failingNode) Transactions(query string, page int, perPage int) ([]*TransactionResult, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestTransactionsIterator_errors(t *testing.T) {
+	it := NewTransactionsIterator(failingNode{}, NewTransactionsQuery().Type(1), 0)
+	if it.Next(context.Background()) || it.Err() == nil || it.Err().Error() != "connection refused" {
+		t.Errorf("unexpected iteration, err %v", it.Err())
+	}
+
+	it = NewTransactionsIterator(failingNode{}, NewTransactionsQuery().Type(0), 0)
+	if it.Next(context.Background()) || it.Err() == nil {
+		t.Error("Next want error of invalid query")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	it = NewTransactionsIterator(failingNode{}, NewTransactionsQuery().Type(1), 0)
+	if it.Next(ctx) || it.Err() != context.Canceled {
+		t.Errorf("Err want %v, got %v", context.Canceled, it.Err())
+	}
+}
+
+// Node with validators paging like Tendermint, it counts requests in flight.
+type validatorsNode struct {
+	NodeClient
+	count int
+
+	mu       sync.Mutex
+	requests []int
+}
+
+func (n *validatorsNode) ValidatorsPage(height, page, perPage int) ([]*ValidatorResult, error) {
+	n.mu.Lock()
+	n.requests = append(n.requests, page)
+	n.mu.Unlock()
+	time.Sleep(time.Millisecond)
+
+	pages := (n.count + perPage - 1) / perPage
+	if pages == 0 {
+		pages = 1
+	}
+	if page < 1 || page > pages {
+		return nil, &Error{Code: 500, Message: fmt.Sprintf("page should be within [1, %d] range, given %d", pages, page)}
+	}
+	var result []*ValidatorResult
+	for i := (page - 1) * perPage; i < page*perPage && i < n.count; i++ {
+		result = append(result, &ValidatorResult{PubKey: "Mp" + strconv.Itoa(i), VotingPower: strconv.Itoa(height)})
+	}
+	return result, nil
+}
+
+func TestValidatorsIterator(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		t.Run(fmt.Sprint("prefetch ", prefetch), func(t *testing.T) {
+			node := &validatorsNode{count: 25}
+			var opts []IteratorOption
+			if prefetch {
+				opts = append(opts, WithPrefetch())
+			}
+			it := NewValidatorsIterator(node, 7, 10, opts...)
+			var keys []string
+			for it.Next(context.Background()) {
+				if it.Validator().VotingPower != "7" {
+					t.Fatalf("unexpected height of validator %+v", it.Validator())
+				}
+				keys = append(keys, it.Validator().PubKey)
+			}
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if len(keys) != 25 || keys[0] != "Mp0" || keys[24] != "Mp24" {
+				t.Errorf("unexpected validators %v", keys)
+			}
+			if fmt.Sprint(node.requests) != "[1 2 3]" {
+				t.Errorf("unexpected requests %v", node.requests)
+			}
+			if it.Next(context.Background()) {
+				t.Error("Next after the end")
+			}
+		})
+	}
+}
+
+func TestValidatorsIterator_exactPages(t *testing.T) {
+	node := &validatorsNode{count: 20}
+	it := NewValidatorsIterator(node, 0, 10, WithPrefetch())
+	n := 0
+	for it.Next(context.Background()) {
+		n++
+	}
+	if it.Err() != nil || n != 20 {
+		t.Errorf("validators want 20, got %d, err %v", n, it.Err())
+	}
+}
+
+func TestApi_ValidatorsAtHeight_allPages(t *testing.T) {
+	node := &validatorsNode{count: 250}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("perPage"))
+		response := &ValidatorsResponse{Jsonrpc: "2.0"}
+		result, err := node.ValidatorsPage(0, page, perPage)
+		if err != nil {
+			response.Error = err.(*Error)
+		}
+		response.Result = result
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	validators, err := NewApi(server.URL).ValidatorsAtHeight(LatestBlockHeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(validators) != 250 {
+		t.Errorf("validators want 250, got %d", len(validators))
+	}
+}
+
+type candidatesNode struct {
+	NodeClient
+	requests int
+}
+
+func (n *candidatesNode) CandidatesAtHeight(height int, includeStakes bool) ([]*CandidateResult, error) {
+	n.requests++
+	return []*CandidateResult{{PubKey: "Mp01"}, {PubKey: "Mp02"}}, nil
+}
+
+func TestCandidatesIterator(t *testing.T) {
+	node := &candidatesNode{}
+	it := NewCandidatesIterator(node, 0, true)
+	var keys []string
+	for it.Next(context.Background()) {
+		keys = append(keys, it.Candidate().PubKey)
+	}
+	if it.Err() != nil || fmt.Sprint(keys) != "[Mp01 Mp02]" || node.requests != 1 {
+		t.Errorf("unexpected candidates %v, requests %d, err %v", keys, node.requests, it.Err())
+	}
+	if it.Next(context.Background()) || node.requests != 1 {
+		t.Error("Next after the end")
+	}
+}
+
+func TestTransactionsIterator_prefetch(t *testing.T) {
+	node := &transactionsNode{transactions: map[string][]*TransactionResult{
+		"tags.tx.type='01'": transactionResults("Mt", 35),
+	}}
+	hashes := collectTransactions(t, NewTransactionsIterator(node, NewTransactionsQuery().Type(1), 10, WithPrefetch()))
+	if len(hashes) != 35 || hashes[34] != "Mt34" {
+		t.Errorf("unexpected transactions %v", hashes)
+	}
+}
@@ -1,5 +1,3 @@
-// +build integration
-
 package api
 
 import (
@@ -9,6 +7,7 @@ import (
 func TestApi_MissedBlocks(t *testing.T) {
 	responseValidators, err := testApi.ValidatorsAtHeight(0)
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 	if len(responseValidators) == 0 {
@@ -16,6 +15,7 @@ func TestApi_MissedBlocks(t *testing.T) {
 	}
 	response, err := testApi.MissedBlocksAtHeight(responseValidators[0].PubKey, LatestBlockHeight)
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 	t.Logf("%+v", response)
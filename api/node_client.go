@@ -0,0 +1,28 @@
+package api
+
+// NodeClient is the set of node methods implemented by both transports: REST Api and gRPC client adapter
+// returned by grpc_client.Client.NodeClient(). Use it to switch between transports by configuration.
+// Height 0 (LatestBlockHeight) means the latest block.
+type NodeClient interface {
+	Status() (*StatusResult, error)
+	AddressAtHeight(address string, height int) (*AddressResult, error)
+	Nonce(address string) (uint64, error)
+	Block(height int) (*BlockResult, error)
+	CandidateAtHeight(pubKey string, height int) (*CandidateResult, error)
+	CandidatesAtHeight(height int, includeStakes bool) ([]*CandidateResult, error)
+	CoinInfoAtHeight(symbol string, height int) (*CoinInfoResult, error)
+	EstimateCoinBuyAtHeight(coinToSell string, valueToBuy string, coinToBuy string, height int) (*EstimateCoinBuyResult, error)
+	EstimateCoinSellAtHeight(coinToSell string, valueToSell string, coinToBuy string, height int) (*EstimateCoinSellResult, error)
+	EstimateCoinSellAllAtHeight(coinToSell string, coinToBuy string, valueToSell string, gasPrice int, height int) (*EstimateCoinSellAllResult, error)
+	EventsAtHeight(height int) (*EventsResult, error)
+	MaxGas() (string, error)
+	MinGasPrice() (string, error)
+	MissedBlocksAtHeight(pubKey string, height int) (*MissedBlocksResult, error)
+	SendRawTransaction(tx string) (*SendTransactionResult, error)
+	Transaction(hash string) (*TransactionResult, error)
+	Transactions(query string, page int, perPage int) ([]*TransactionResult, error)
+	UnconfirmedTxs(limit int) (*UnconfirmedTxsResult, error)
+	ValidatorsPage(height, page, perPage int) ([]*ValidatorResult, error)
+}
+
+var _ NodeClient = (*Api)(nil)
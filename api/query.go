@@ -0,0 +1,211 @@
+package api
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/nikolaev-dev/sdk/wallet"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var ErrEmptyQuery = errors.New("empty query")
+
+type queryCondition struct {
+	key      string
+	operator string
+	value    string
+}
+
+func (c queryCondition) String() string {
+	return c.key + c.operator + c.value
+}
+
+// Typed query of transactions search, e.g.
+//
+//	api.NewTransactionsQuery().From("Mx...").Type(1).Or(api.NewTransactionsQuery().To("Mx..."))
+//
+// Tendermint query language supports only AND, so query with OR is rendered as several queries,
+// one per alternative. The first invalid argument is reported by Queries.
+type TransactionsQuery struct {
+	// disjunction of conjunctions
+	alternatives [][]queryCondition
+	err          error
+}
+
+// Create query matching all transactions, add conditions to narrow it.
+func NewTransactionsQuery() *TransactionsQuery {
+	return &TransactionsQuery{alternatives: [][]queryCondition{nil}}
+}
+
+func (q *TransactionsQuery) where(key, operator, value string) *TransactionsQuery {
+	for i := range q.alternatives {
+		q.alternatives[i] = append(q.alternatives[i], queryCondition{key: key, operator: operator, value: value})
+	}
+	return q
+}
+
+func (q *TransactionsQuery) setErr(err error) *TransactionsQuery {
+	if q.err == nil {
+		q.err = err
+	}
+	return q
+}
+
+func (q *TransactionsQuery) address(tag, address string) *TransactionsQuery {
+	a, err := wallet.ParseAddress(address)
+	if err != nil {
+		return q.setErr(fmt.Errorf("%s: %w", tag, err))
+	}
+	return q.where(tag, "=", quoteQueryValue(hex.EncodeToString(a[:])))
+}
+
+// Transactions sent from address.
+func (q *TransactionsQuery) From(address string) *TransactionsQuery {
+	return q.address("tags.tx.from", address)
+}
+
+// Transactions sent to address.
+func (q *TransactionsQuery) To(address string) *TransactionsQuery {
+	return q.address("tags.tx.to", address)
+}
+
+var coinSymbol = regexp.MustCompile(`^[A-Z0-9]{3,10}$`)
+
+func (q *TransactionsQuery) coin(tag, symbol string) *TransactionsQuery {
+	symbol = strings.ToUpper(symbol)
+	if !coinSymbol.MatchString(symbol) {
+		return q.setErr(fmt.Errorf("%s: invalid coin symbol %q", tag, symbol))
+	}
+	return q.where(tag, "=", quoteQueryValue(symbol))
+}
+
+// Transactions of coin, e.g. Send, Delegate or CreateCoin.
+func (q *TransactionsQuery) Coin(symbol string) *TransactionsQuery {
+	return q.coin("tags.tx.coin", symbol)
+}
+
+// Transactions selling the coin.
+func (q *TransactionsQuery) CoinToSell(symbol string) *TransactionsQuery {
+	return q.coin("tags.tx.coin_to_sell", symbol)
+}
+
+// Transactions buying the coin.
+func (q *TransactionsQuery) CoinToBuy(symbol string) *TransactionsQuery {
+	return q.coin("tags.tx.coin_to_buy", symbol)
+}
+
+// Transactions of type, e.g. transaction.TypeSend.
+func (q *TransactionsQuery) Type(t int) *TransactionsQuery {
+	if t < 1 || t > 0xff {
+		return q.setErr(fmt.Errorf("tags.tx.type: invalid type %d", t))
+	}
+	return q.where("tags.tx.type", "=", quoteQueryValue(hex.EncodeToString([]byte{byte(t)})))
+}
+
+// Transactions of block at height.
+func (q *TransactionsQuery) Height(height int) *TransactionsQuery {
+	return q.HeightRange(height, height)
+}
+
+// Transactions of blocks from..to inclusive, 0 means no bound.
+func (q *TransactionsQuery) HeightRange(from, to int) *TransactionsQuery {
+	if from < 0 || to < 0 || (to != 0 && from > to) {
+		return q.setErr(fmt.Errorf("tx.height: invalid range %d..%d", from, to))
+	}
+	if from == to && from != 0 {
+		return q.where("tx.height", "=", strconv.Itoa(from))
+	}
+	if from != 0 {
+		q.where("tx.height", ">=", strconv.Itoa(from))
+	}
+	if to != 0 {
+		q.where("tx.height", "<=", strconv.Itoa(to))
+	}
+	return q
+}
+
+var tagKey = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.]*$`)
+
+// Transactions with tag equal to value, value is quoted. Quotes are not allowed in value by Tendermint query syntax.
+func (q *TransactionsQuery) Tag(key, value string) *TransactionsQuery {
+	if !tagKey.MatchString(key) {
+		return q.setErr(fmt.Errorf("invalid tag key %q", key))
+	}
+	if strings.ContainsAny(value, `'"`) {
+		return q.setErr(fmt.Errorf("%s: quotes are not allowed in value %q", key, value))
+	}
+	return q.where(key, "=", quoteQueryValue(value))
+}
+
+// Add conditions of other query to the query: (q) AND (other).
+func (q *TransactionsQuery) And(other *TransactionsQuery) *TransactionsQuery {
+	if other.err != nil {
+		q.setErr(other.err)
+	}
+	alternatives := make([][]queryCondition, 0, len(q.alternatives)*len(other.alternatives))
+	for _, a := range q.alternatives {
+		for _, b := range other.alternatives {
+			conditions := make([]queryCondition, 0, len(a)+len(b))
+			alternatives = append(alternatives, append(append(conditions, a...), b...))
+		}
+	}
+	q.alternatives = alternatives
+	return q
+}
+
+// Add alternative: (q) OR (other).
+func (q *TransactionsQuery) Or(other *TransactionsQuery) *TransactionsQuery {
+	if other.err != nil {
+		q.setErr(other.err)
+	}
+	for _, b := range other.alternatives {
+		q.alternatives = append(q.alternatives, append([]queryCondition(nil), b...))
+	}
+	return q
+}
+
+// Returns query strings of alternatives, results of all queries should be merged.
+// Returns ErrEmptyQuery if an alternative has no conditions, the node does not search without conditions.
+func (q *TransactionsQuery) Queries() ([]string, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	queries := make([]string, 0, len(q.alternatives))
+	seen := make(map[string]bool, len(q.alternatives))
+	for _, conditions := range q.alternatives {
+		if len(conditions) == 0 {
+			return nil, ErrEmptyQuery
+		}
+		parts := make([]string, len(conditions))
+		for i, condition := range conditions {
+			parts[i] = condition.String()
+		}
+		query := strings.Join(parts, " AND ")
+		if !seen[query] {
+			seen[query] = true
+			queries = append(queries, query)
+		}
+	}
+	return queries, nil
+}
+
+// Returns query string, alternatives are joined with OR which is supported by Tendermint 0.34 and later only.
+func (q *TransactionsQuery) String() string {
+	queries, err := q.Queries()
+	if err != nil {
+		return ""
+	}
+	if len(queries) == 1 {
+		return queries[0]
+	}
+	for i, query := range queries {
+		queries[i] = "(" + query + ")"
+	}
+	return strings.Join(queries, " OR ")
+}
+
+func quoteQueryValue(value string) string {
+	return "'" + value + "'"
+}
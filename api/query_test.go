@@ -0,0 +1,97 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTransactionsQuery_Queries(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *TransactionsQuery
+		want  []string
+	}{
+		{
+			name:  "from and type",
+			query: NewTransactionsQuery().From("Mx1B685a7c1e78726c48f619c497a07ed75fe00483").Type(1),
+			want:  []string{"tags.tx.from='1b685a7c1e78726c48f619c497a07ed75fe00483' AND tags.tx.type='01'"},
+		},
+		{
+			name:  "coin and height range",
+			query: NewTransactionsQuery().Coin("mnt").HeightRange(10, 20),
+			want:  []string{"tags.tx.coin='MNT' AND tx.height>=10 AND tx.height<=20"},
+		},
+		{
+			name:  "height",
+			query: NewTransactionsQuery().Height(5).Type(13),
+			want:  []string{"tx.height=5 AND tags.tx.type='0d'"},
+		},
+		{
+			name:  "open height range",
+			query: NewTransactionsQuery().CoinToSell("BIP").CoinToBuy("TEST").HeightRange(7, 0),
+			want:  []string{"tags.tx.coin_to_sell='BIP' AND tags.tx.coin_to_buy='TEST' AND tx.height>=7"},
+		},
+		{
+			name: "or",
+			query: NewTransactionsQuery().From("Mx1b685a7c1e78726c48f619c497a07ed75fe00483").
+				Or(NewTransactionsQuery().To("Mx1b685a7c1e78726c48f619c497a07ed75fe00483")),
+			want: []string{
+				"tags.tx.from='1b685a7c1e78726c48f619c497a07ed75fe00483'",
+				"tags.tx.to='1b685a7c1e78726c48f619c497a07ed75fe00483'",
+			},
+		},
+		{
+			name: "and of or",
+			query: NewTransactionsQuery().Coin("MNT").Or(NewTransactionsQuery().Coin("BIP")).
+				And(NewTransactionsQuery().Tag("tags.tx.return", "1")),
+			want: []string{
+				"tags.tx.coin='MNT' AND tags.tx.return='1'",
+				"tags.tx.coin='BIP' AND tags.tx.return='1'",
+			},
+		},
+		{
+			name:  "duplicate alternatives",
+			query: NewTransactionsQuery().Type(1).Or(NewTransactionsQuery().Type(1)),
+			want:  []string{"tags.tx.type='01'"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.query.Queries()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Queries want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTransactionsQuery_String(t *testing.T) {
+	query := NewTransactionsQuery().Type(1).Or(NewTransactionsQuery().Type(2).Height(3))
+	want := "(tags.tx.type='01') OR (tags.tx.type='02' AND tx.height=3)"
+	if query.String() != want {
+		t.Errorf("String want %q, got %q", want, query.String())
+	}
+}
+
+func TestTransactionsQuery_Queries_invalid(t *testing.T) {
+	tests := map[string]*TransactionsQuery{
+		"address":     NewTransactionsQuery().From("1b685a7c1e78726c48f619c497a07ed75fe00483"),
+		"coin":        NewTransactionsQuery().Coin("M'NT"),
+		"type":        NewTransactionsQuery().Type(0),
+		"range":       NewTransactionsQuery().HeightRange(5, 4),
+		"tag key":     NewTransactionsQuery().Tag("tags.tx.from='x' OR a", "1"),
+		"tag value":   NewTransactionsQuery().Tag("tags.tx.payload", "it's"),
+		"alternative": NewTransactionsQuery().Type(1).Or(NewTransactionsQuery().Coin("")),
+		"empty":       NewTransactionsQuery(),
+	}
+	for name, query := range tests {
+		t.Run(name, func(t *testing.T) {
+			if queries, err := query.Queries(); err == nil {
+				t.Errorf("Queries want error, got %q", queries)
+			}
+		})
+	}
+}
@@ -1,5 +1,3 @@
-// +build integration
-
 package api
 
 import (
@@ -41,6 +39,7 @@ func TestApi_Send(t *testing.T) {
 
 	nonce, err := testApi.Nonce("Mxeeee1973381ab793719fff497b9a516719fcd5a2")
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 
@@ -55,6 +54,7 @@ func TestApi_Send(t *testing.T) {
 
 	res, err := testApi.SendTransaction(signedTransaction)
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 	t.Logf("%+v", res)
@@ -0,0 +1,435 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/net/websocket"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Decoded event of Tendermint subscription. Block is set for "tm.event='NewBlock'" events, Transaction is set for "tm.event='Tx'" events.
+type SubscriptionEvent struct {
+	Query       string
+	Height      int
+	Events      map[string][]string
+	Block       *BlockResult
+	Transaction *TransactionResult
+}
+
+// SubscriberOption configures the subscriber created by NewSubscriber.
+type SubscriberOption func(*subscriberOptions)
+
+type subscriberOptions struct {
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	buffer     int
+	onError    func(error)
+	tlsConfig  *tls.Config
+}
+
+// Set exponential backoff of reconnections, 1s and 30s by default.
+func WithSubscriberBackoff(min, max time.Duration) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.minBackoff, o.maxBackoff = min, max
+	}
+}
+
+// Set events channel buffer size, 100 by default.
+func WithSubscriberBuffer(size int) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.buffer = size
+	}
+}
+
+// Set handler of transient errors which caused reconnection.
+func WithSubscriberErrorHandler(f func(error)) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.onError = f
+	}
+}
+
+// Set TLS config of wss:// connections.
+func WithSubscriberTLS(config *tls.Config) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.tlsConfig = config
+	}
+}
+
+// Subscriber of the node Tendermint RPC WebSocket endpoint.
+type Subscriber struct {
+	endpoint string
+	node     NodeClient
+	options  *subscriberOptions
+}
+
+// Create subscriber of Tendermint RPC endpoint, e.g. "ws://localhost:26657/websocket".
+// Path "/websocket" is used if the endpoint has no path, http(s) schemes are replaced with ws(s).
+// If node is not nil, events are completed with Transaction and Block results fetched from it,
+// otherwise they are decoded from the event data only: transactions have hash, raw tx, height, index, code, log and tags,
+// blocks have height, time and transactions count.
+func NewSubscriber(endpoint string, node NodeClient, opts ...SubscriberOption) *Subscriber {
+	o := &subscriberOptions{minBackoff: time.Second, maxBackoff: 30 * time.Second, buffer: 100}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &Subscriber{endpoint: endpoint, node: node, options: o}
+}
+
+// Subscription to events of the query over its own WebSocket connection.
+type Subscription struct {
+	subscriber *Subscriber
+	query      string
+	events     chan *SubscriptionEvent
+	err        error
+}
+
+// Subscribe to events by query, e.g. "tm.event='Tx' AND tags.tx.from='...'" or "tm.event='NewBlock'".
+// The subscription reconnects with backoff when the connection is broken, events emitted while it was disconnected are lost.
+// The channel is closed when the context is done or the node rejected the query.
+func (s *Subscriber) Subscribe(ctx context.Context, query string) *Subscription {
+	subscription := &Subscription{
+		subscriber: s,
+		query:      query,
+		events:     make(chan *SubscriptionEvent, s.options.buffer),
+	}
+	go subscription.run(ctx)
+	return subscription
+}
+
+// Returns channel of events.
+func (s *Subscription) Events() <-chan *SubscriptionEvent {
+	return s.events
+}
+
+// Returns the reason of subscription end, valid after the events channel is closed.
+// It is the context error or *Error returned by the node on subscribe.
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+type rpcRequest struct {
+	Jsonrpc string      `json:"jsonrpc"`
+	ID      string      `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+type rpcEvent struct {
+	Query string `json:"query"`
+	Data  *struct {
+		Type  string          `json:"type"`
+		Value json.RawMessage `json:"value"`
+	} `json:"data"`
+	Events map[string][]string `json:"events"`
+}
+
+type rpcTxValue struct {
+	TxResult struct {
+		Height string `json:"height"`
+		Index  int    `json:"index"`
+		Tx     []byte `json:"tx"`
+		Result struct {
+			Code uint32 `json:"code"`
+			Log  string `json:"log"`
+		} `json:"result"`
+	} `json:"TxResult"`
+}
+
+type rpcBlockValue struct {
+	Block struct {
+		Header struct {
+			Height string    `json:"height"`
+			Time   time.Time `json:"time"`
+			NumTxs string    `json:"num_txs"`
+		} `json:"header"`
+		Data struct {
+			Txs [][]byte `json:"txs"`
+		} `json:"data"`
+	} `json:"block"`
+}
+
+// Error returned by the node on subscribe, it ends the subscription.
+type subscribeError struct {
+	err *Error
+}
+
+func (e subscribeError) Error() string {
+	return e.err.Error()
+}
+
+func (s *Subscription) run(ctx context.Context) {
+	defer close(s.events)
+
+	options := s.subscriber.options
+	backoff := options.minBackoff
+	for {
+		received, err := s.session(ctx)
+		if ctx.Err() != nil {
+			s.err = ctx.Err()
+			return
+		}
+		if e, ok := err.(subscribeError); ok {
+			s.err = e.err
+			return
+		}
+		if options.onError != nil && err != nil {
+			options.onError(err)
+		}
+		if received {
+			backoff = options.minBackoff
+		}
+
+		select {
+		case <-ctx.Done():
+			s.err = ctx.Err()
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > options.maxBackoff {
+			backoff = options.maxBackoff
+		}
+	}
+}
+
+// One connection of the subscription. Reports whether at least one event was received.
+func (s *Subscription) session(ctx context.Context) (bool, error) {
+	conn, err := s.subscriber.dial(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		conn.Close()
+	}()
+	defer wg.Wait()
+	defer close(done)
+
+	err = websocket.JSON.Send(conn, &rpcRequest{
+		Jsonrpc: "2.0",
+		ID:      "0",
+		Method:  "subscribe",
+		Params:  map[string]string{"query": s.query},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	received := false
+	for {
+		var response rpcResponse
+		err := websocket.JSON.Receive(conn, &response)
+		if err != nil {
+			return received, err
+		}
+		if response.Error != nil {
+			if !received {
+				return false, subscribeError{response.Error}
+			}
+			return received, response.Error
+		}
+
+		var result rpcEvent
+		if len(response.Result) != 0 {
+			err = json.Unmarshal(response.Result, &result)
+			if err != nil {
+				return received, err
+			}
+		}
+		if result.Data == nil {
+			// subscription confirmation
+			continue
+		}
+		received = true
+
+		event, err := s.decode(&result)
+		if err != nil {
+			return received, err
+		}
+		select {
+		case <-ctx.Done():
+			return received, ctx.Err()
+		case s.events <- event:
+		}
+	}
+}
+
+func (s *Subscription) decode(result *rpcEvent) (*SubscriptionEvent, error) {
+	event := &SubscriptionEvent{Query: result.Query, Events: result.Events}
+	node := s.subscriber.node
+
+	switch result.Data.Type {
+	case "tendermint/event/Tx":
+		value := new(rpcTxValue)
+		err := json.Unmarshal(result.Data.Value, value)
+		if err != nil {
+			return nil, err
+		}
+		transaction := transactionFromEvent(value, result.Events)
+		event.Height, err = strconv.Atoi(transaction.Height)
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			transaction, err = node.Transaction(transaction.Hash)
+			if err != nil {
+				return nil, err
+			}
+		}
+		event.Transaction = transaction
+	case "tendermint/event/NewBlock":
+		value := new(rpcBlockValue)
+		err := json.Unmarshal(result.Data.Value, value)
+		if err != nil {
+			return nil, err
+		}
+		header := value.Block.Header
+		event.Height, err = strconv.Atoi(header.Height)
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			event.Block, err = node.Block(event.Height)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+		numTxs := header.NumTxs
+		if numTxs == "" {
+			numTxs = strconv.Itoa(len(value.Block.Data.Txs))
+		}
+		event.Block = &BlockResult{Height: header.Height, Time: header.Time, NumTxs: numTxs}
+	default:
+		if heights := result.Events["tx.height"]; len(heights) != 0 {
+			event.Height, _ = strconv.Atoi(heights[0])
+		}
+	}
+
+	return event, nil
+}
+
+func transactionFromEvent(value *rpcTxValue, events map[string][]string) *TransactionResult {
+	result := value.TxResult
+	transaction := &TransactionResult{
+		RawTx:  hex.EncodeToString(result.Tx),
+		Height: result.Height,
+		Index:  result.Index,
+		Code:   result.Result.Code,
+		Log:    result.Result.Log,
+	}
+
+	if hashes := events["tx.hash"]; len(hashes) != 0 {
+		transaction.Hash = "Mt" + strings.ToLower(hashes[0])
+	} else {
+		hash := sha256.Sum256(result.Tx)
+		transaction.Hash = "Mt" + hex.EncodeToString(hash[:])
+	}
+	if transaction.Height == "" {
+		if heights := events["tx.height"]; len(heights) != 0 {
+			transaction.Height = heights[0]
+		}
+	}
+
+	tags := make(map[string]string)
+	for key, values := range events {
+		if strings.HasPrefix(key, "tags.") && len(values) != 0 {
+			tags[strings.TrimPrefix(key, "tags.")] = values[0]
+		}
+	}
+	if bytes, err := json.Marshal(tags); err == nil {
+		_ = json.Unmarshal(bytes, &transaction.Tags)
+	}
+
+	return transaction
+}
+
+func (s *Subscriber) dial(ctx context.Context) (*websocket.Conn, error) {
+	location, err := url.Parse(s.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	switch location.Scheme {
+	case "http":
+		location.Scheme = "ws"
+	case "https":
+		location.Scheme = "wss"
+	case "ws", "wss":
+	default:
+		return nil, fmt.Errorf("unsupported scheme of subscriber endpoint: %q", location.Scheme)
+	}
+	if location.Path == "" || location.Path == "/" {
+		location.Path = "/websocket"
+	}
+
+	origin := *location
+	origin.Scheme = strings.Replace(origin.Scheme, "ws", "http", 1)
+	origin.Path, origin.RawQuery = "/", ""
+	config, err := websocket.NewConfig(location.String(), origin.String())
+	if err != nil {
+		return nil, err
+	}
+
+	host := location.Host
+	if location.Port() == "" {
+		if location.Scheme == "wss" {
+			host = net.JoinHostPort(location.Hostname(), "443")
+		} else {
+			host = net.JoinHostPort(location.Hostname(), "80")
+		}
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+	if location.Scheme == "wss" {
+		tlsConfig := s.options.tlsConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig = tlsConfig.Clone()
+			tlsConfig.ServerName = location.Hostname()
+		}
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	// handshake is not cancelled by the context, limit it by deadline
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(time.Minute))
+	}
+	ws, err := websocket.NewClient(config, conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return ws, nil
+}
@@ -0,0 +1,246 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"golang.org/x/net/websocket"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Local Tendermint RPC WebSocket server, every accepted subscription is sent to conns.
+type tendermintServer struct {
+	*httptest.Server
+	conns chan *tendermintConn
+}
+
+type tendermintConn struct {
+	ws    *websocket.Conn
+	id    json.RawMessage
+	query string
+	done  chan struct{}
+}
+
+func newTendermintServer(t *testing.T, reject bool) *tendermintServer {
+	s := &tendermintServer{conns: make(chan *tendermintConn, 10)}
+	s.Server = httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		var request struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params map[string]string `json:"params"`
+		}
+		if err := websocket.JSON.Receive(ws, &request); err != nil {
+			return
+		}
+		if request.Method != "subscribe" {
+			t.Errorf("unexpected method %q", request.Method)
+			return
+		}
+		if reject {
+			_ = websocket.JSON.Send(ws, map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      request.ID,
+				"error":   map[string]interface{}{"code": -32603, "message": "Internal error", "data": "failed to parse query"},
+			})
+			return
+		}
+		_ = websocket.JSON.Send(ws, map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": map[string]interface{}{}})
+
+		conn := &tendermintConn{ws: ws, id: request.ID, query: request.Params["query"], done: make(chan struct{})}
+		s.conns <- conn
+		<-conn.done
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *tendermintServer) accept(t *testing.T) *tendermintConn {
+	select {
+	case conn := <-s.conns:
+		return conn
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription is not received")
+		return nil
+	}
+}
+
+func (c *tendermintConn) send(t *testing.T, data map[string]interface{}, events map[string][]string) {
+	err := websocket.JSON.Send(c.ws, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      json.RawMessage(`"0#event"`),
+		"result":  map[string]interface{}{"query": c.query, "data": data, "events": events},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *tendermintConn) close() {
+	close(c.done)
+}
+
+func txEventData(height int) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "tendermint/event/Tx",
+		"value": map[string]interface{}{
+			"TxResult": map[string]interface{}{
+				"height": strconv.Itoa(height),
+				"index":  1,
+				"tx":     []byte{0xf8, 0x01},
+				"result": map[string]interface{}{"code": 0, "log": ""},
+			},
+		},
+	}
+}
+
+func blockEventData(height int) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "tendermint/event/NewBlock",
+		"value": map[string]interface{}{
+			"block": map[string]interface{}{
+				"header": map[string]interface{}{"height": strconv.Itoa(height), "time": "2020-04-09T11:27:24.530403396Z"},
+				"data":   map[string]interface{}{"txs": [][]byte{{1}, {2}}},
+			},
+		},
+	}
+}
+
+func receiveEvent(t *testing.T, subscription *Subscription) *SubscriptionEvent {
+	select {
+	case event, ok := <-subscription.Events():
+		if !ok {
+			t.Fatalf("subscription is closed: %v", subscription.Err())
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("event is not received")
+		return nil
+	}
+}
+
+func TestSubscriber_Subscribe_transaction(t *testing.T) {
+	server := newTendermintServer(t, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	query := "tm.event='Tx' AND tags.tx.from='e2a0fa2a5c13b67ca9e4e0e0f1c0f1dfc0b2c7f3'"
+	subscription := NewSubscriber(server.URL, nil).Subscribe(ctx, query)
+	conn := server.accept(t)
+	if conn.query != query {
+		t.Errorf("query want %q, got %q", query, conn.query)
+	}
+
+	conn.send(t, txEventData(12), map[string][]string{
+		"tm.event":     {"Tx"},
+		"tx.hash":      {"8D0F6A2B4E1C3D5F"},
+		"tx.height":    {"12"},
+		"tags.tx.from": {"e2a0fa2a5c13b67ca9e4e0e0f1c0f1dfc0b2c7f3"},
+		"tags.tx.type": {"01"},
+	})
+	event := receiveEvent(t, subscription)
+	if event.Height != 12 || event.Query != query || event.Block != nil {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	tx := event.Transaction
+	if tx.Hash != "Mt8d0f6a2b4e1c3d5f" {
+		t.Errorf("hash want %s, got %s", "Mt8d0f6a2b4e1c3d5f", tx.Hash)
+	}
+	if tx.RawTx != "f801" || tx.Height != "12" || tx.Index != 1 {
+		t.Errorf("unexpected transaction %+v", tx)
+	}
+	if tx.Tags.TxFrom != "e2a0fa2a5c13b67ca9e4e0e0f1c0f1dfc0b2c7f3" || tx.Tags.TxType != "01" {
+		t.Errorf("unexpected tags %+v", tx.Tags)
+	}
+
+	cancel()
+	if _, ok := <-subscription.Events(); ok {
+		t.Fatal("events channel is not closed")
+	}
+	if subscription.Err() != context.Canceled {
+		t.Errorf("err want %v, got %v", context.Canceled, subscription.Err())
+	}
+}
+
+type blockNode struct {
+	NodeClient
+}
+
+func (blockNode) Block(height int) (*BlockResult, error) {
+	return &BlockResult{Height: strconv.Itoa(height), Hash: "Mh" + strconv.Itoa(height)}, nil
+}
+
+func TestSubscriber_Subscribe_block(t *testing.T) {
+	server := newTendermintServer(t, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subscription := NewSubscriber(server.URL, nil).Subscribe(ctx, "tm.event='NewBlock'")
+	conn := server.accept(t)
+	conn.send(t, blockEventData(7), map[string][]string{"tm.event": {"NewBlock"}})
+	event := receiveEvent(t, subscription)
+	if event.Height != 7 || event.Block.Height != "7" || event.Block.NumTxs != "2" || event.Block.Time.IsZero() {
+		t.Errorf("unexpected block %+v", event.Block)
+	}
+
+	subscription = NewSubscriber(server.URL, blockNode{}).Subscribe(ctx, "tm.event='NewBlock'")
+	conn = server.accept(t)
+	conn.send(t, blockEventData(8), nil)
+	event = receiveEvent(t, subscription)
+	if event.Height != 8 || event.Block.Hash != "Mh8" {
+		t.Errorf("unexpected block %+v", event.Block)
+	}
+}
+
+func TestSubscriber_Subscribe_reconnect(t *testing.T) {
+	server := newTendermintServer(t, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errs := make(chan error, 10)
+	subscriber := NewSubscriber(server.URL, nil,
+		WithSubscriberBackoff(10*time.Millisecond, 100*time.Millisecond),
+		WithSubscriberErrorHandler(func(err error) { errs <- err }),
+	)
+	subscription := subscriber.Subscribe(ctx, "tm.event='NewBlock'")
+
+	conn := server.accept(t)
+	conn.send(t, blockEventData(1), nil)
+	if event := receiveEvent(t, subscription); event.Height != 1 {
+		t.Fatalf("height want 1, got %d", event.Height)
+	}
+	conn.close()
+
+	conn = server.accept(t)
+	conn.send(t, blockEventData(3), nil)
+	if event := receiveEvent(t, subscription); event.Height != 3 {
+		t.Fatalf("height want 3, got %d", event.Height)
+	}
+	select {
+	case <-errs:
+	default:
+		t.Error("error handler is not called on reconnection")
+	}
+}
+
+func TestSubscriber_Subscribe_rejected(t *testing.T) {
+	server := newTendermintServer(t, true)
+
+	subscription := NewSubscriber(server.URL, nil).Subscribe(context.Background(), "tm.event=")
+	select {
+	case _, ok := <-subscription.Events():
+		if ok {
+			t.Fatal("unexpected event")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription is not closed")
+	}
+	err, ok := subscription.Err().(*Error)
+	if !ok {
+		t.Fatalf("err want *Error, got %T", subscription.Err())
+	}
+	if err.Data != "failed to parse query" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
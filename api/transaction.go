@@ -71,6 +71,32 @@ func (t *TransactionResult) DataStruct() (tdi, error) {
 	switch transaction.Type(t.Type) {
 	case transaction.TypeSend:
 		data = &SendData{}
+	case transaction.TypeSellCoin:
+		data = &SellCoinData{}
+	case transaction.TypeSellAllCoin:
+		data = &SellAllCoinData{}
+	case transaction.TypeBuyCoin:
+		data = &BuyCoinData{}
+	case transaction.TypeCreateCoin:
+		data = &CreateCoinData{}
+	case transaction.TypeDeclareCandidacy:
+		data = &DeclareCandidacyData{}
+	case transaction.TypeDelegate:
+		data = &DelegateData{}
+	case transaction.TypeUnbond:
+		data = &UnbondData{}
+	case transaction.TypeRedeemCheck:
+		data = &RedeemCheckData{}
+	case transaction.TypeSetCandidateOnline:
+		data = &SetCandidateOnData{}
+	case transaction.TypeSetCandidateOffline:
+		data = &SetCandidateOffData{}
+	case transaction.TypeCreateMultisig:
+		data = &CreateMultisigData{}
+	case transaction.TypeMultisend:
+		data = &MultisendData{}
+	case transaction.TypeEditCandidate:
+		data = &EditCandidateData{}
 	default:
 		return nil, errors.New("unknown transaction type")
 	}
@@ -205,9 +231,9 @@ func (s *EditCandidateData) fill(b []byte) error {
 }
 
 type CreateMultisigData struct {
-	Threshold uint       `json:"threshold"`
-	Weights   []uint     `json:"weights"`
-	Addresses [][20]byte `json:"addresses"`
+	Threshold string   `json:"threshold"`
+	Weights   []string `json:"weights"`
+	Addresses []string `json:"addresses"`
 }
 
 func (s *CreateMultisigData) fill(b []byte) error {
@@ -215,7 +241,7 @@ func (s *CreateMultisigData) fill(b []byte) error {
 }
 
 type MultisendData struct {
-	List []MultisendDataItem
+	List []MultisendDataItem `json:"list"`
 }
 
 func (s *MultisendData) fill(b []byte) error {
@@ -1,5 +1,3 @@
-// +build integration
-
 package api
 
 import "testing"
@@ -7,6 +5,7 @@ import "testing"
 func TestApi_Transaction(t *testing.T) {
 	response, err := testApi.Transaction("Mtdd7181de1397eed1513928bb4463cf43fc3cbb5d5056018c063b02113517eca0")
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 
@@ -1,5 +1,3 @@
-// +build integration
-
 package api
 
 import "testing"
@@ -7,6 +5,7 @@ import "testing"
 func TestApi_Transactions(t *testing.T) {
 	response, err := testApi.Transactions("tags.tx.type='05'", 0, 0)
 	if err != nil {
+		skipNotRecorded(t, err)
 		t.Fatal(err)
 	}
 	t.Logf("%+v", response)
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"strconv"
 )
@@ -22,9 +23,17 @@ func (a *Api) Validators() ([]*ValidatorResult, error) {
 	return a.ValidatorsAtHeight(LatestBlockHeight)
 }
 
-// Returns list of active validators.
+// Returns list of active validators, all pages are requested.
 func (a *Api) ValidatorsAtHeight(height int) ([]*ValidatorResult, error) {
-	return a.ValidatorsPage(height, 1, 100)
+	var validators []*ValidatorResult
+	it := NewValidatorsIterator(a, height, DefaultPerPage)
+	for it.Next(context.Background()) {
+		validators = append(validators, it.Validator())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return validators, nil
 }
 
 // Returns list of active validators with custom paging.
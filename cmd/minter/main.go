@@ -0,0 +1,216 @@
+// Command minter is a command line client of Minter network: wallet keys, offline building and signing
+// of transactions and queries to node API. Results are printed to stdout as JSON.
+//
+//	minter [-network testnet] [-node URL] <command> [arguments]
+//
+// Run "minter help" for the list of commands.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/nikolaev-dev/sdk/api"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// Context of command run.
+type env struct {
+	profile  *Profile
+	profiles map[string]*Profile
+	stdin    io.Reader
+	stdout   io.Writer
+	stderr   io.Writer
+}
+
+// Create client of the node of the profile.
+func (e *env) api() *api.Api {
+	return api.NewApi(e.profile.Node)
+}
+
+// Print result as indented JSON.
+func (e *env) print(v interface{}) error {
+	encoder := json.NewEncoder(e.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+type command struct {
+	usage string
+	help  string
+	run   func(e *env, args []string) error
+}
+
+var commands map[string]*command
+
+func init() {
+	commands = map[string]*command{
+		"wallet":    {usage: "wallet new|import|derive [flags]", help: "generate, import and derive wallet keys", run: runWallet},
+		"tx":        {usage: "tx build|sign|decode|send [flags]", help: "build, sign, decode and send transactions", run: runTx},
+		"address":   {usage: "address [-height N] <Mx...>", help: "show balance and nonce of address", run: runAddress},
+		"block":     {usage: "block <height>", help: "show block", run: runBlock},
+		"candidate": {usage: "candidate [-height N] <Mp...>", help: "show candidate", run: runCandidate},
+		"estimate":  {usage: "estimate buy|sell|tx [flags]", help: "estimate exchange of coins or commission of transaction", run: runEstimate},
+		"profiles":  {usage: "profiles", help: "list network profiles", run: runProfiles},
+	}
+}
+
+// Error of command line arguments, the usage is printed and the exit code is 2.
+type usageError struct {
+	usage string
+	err   error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func usagef(usage, format string, a ...interface{}) error {
+	return &usageError{usage: usage, err: fmt.Errorf(format, a...)}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("minter", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	network := flags.String("network", envOr("MINTER_NETWORK", "testnet"), "network profile")
+	node := flags.String("node", os.Getenv("MINTER_NODE"), "URL of node API, overrides node of the profile")
+	profilesFile := flags.String("profiles", envOr("MINTER_PROFILES", defaultProfilesFile()), "JSON file with network profiles")
+	flags.Usage = func() { printUsage(flags, stderr) }
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 || flags.Arg(0) == "help" {
+		flags.Usage()
+		if flags.NArg() == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "minter: unknown command %q\n", flags.Arg(0))
+		flags.Usage()
+		return 2
+	}
+
+	profiles, err := LoadProfiles(*profilesFile)
+	if err != nil {
+		fmt.Fprintln(stderr, "minter:", err)
+		return 1
+	}
+	profile, ok := profiles[*network]
+	if !ok {
+		fmt.Fprintf(stderr, "minter: unknown network profile %q\n", *network)
+		return 2
+	}
+	if *node != "" {
+		profile.Node = *node
+	}
+
+	e := &env{profile: profile, profiles: profiles, stdin: stdin, stdout: stdout, stderr: stderr}
+	err = cmd.run(e, flags.Args()[1:])
+	if err == nil {
+		return 0
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		fmt.Fprintf(stderr, "minter: %v\nusage: minter %s\n", usageErr.err, usageErr.usage)
+		return 2
+	}
+	fmt.Fprintln(stderr, "minter:", err)
+	return 1
+}
+
+func printUsage(flags *flag.FlagSet, w io.Writer) {
+	fmt.Fprintln(w, "usage: minter [flags] <command> [arguments]")
+	fmt.Fprintln(w, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-36s %s\n", commands[name].usage, commands[name].help)
+	}
+	fmt.Fprintln(w, "\nflags:")
+	flags.PrintDefaults()
+}
+
+// Create flag set of subcommand printing errors to stderr of env.
+func newFlagSet(e *env, usage string) *flag.FlagSet {
+	flags := flag.NewFlagSet(usage, flag.ContinueOnError)
+	flags.SetOutput(e.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(e.stderr, "usage: minter %s\n", usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// Parse flags of subcommand, flag errors are reported by flag package itself.
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	err := flags.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return &usageError{usage: flags.Name(), err: err}
+	}
+	return err
+}
+
+// Run subcommand of args[0].
+func runSubcommand(e *env, usage string, args []string, subcommands map[string]func(e *env, args []string) error) error {
+	if len(args) == 0 {
+		return usagef(usage, "subcommand is required")
+	}
+	run, ok := subcommands[args[0]]
+	if !ok {
+		return usagef(usage, "unknown subcommand %q", args[0])
+	}
+	return run(e, args[1:])
+}
+
+// Returns the only positional argument, "-" or missing argument are read from stdin.
+func argOrStdin(e *env, flags *flag.FlagSet) (string, error) {
+	if flags.NArg() > 1 {
+		return "", usagef(flags.Name(), "too many arguments")
+	}
+	if flags.NArg() == 1 && flags.Arg(0) != "-" {
+		return flags.Arg(0), nil
+	}
+	b, err := ioutil.ReadAll(e.stdin)
+	if err != nil {
+		return "", err
+	}
+	arg := strings.TrimSpace(string(b))
+	if arg == "" {
+		return "", usagef(flags.Name(), "argument is required")
+	}
+	return arg, nil
+}
+
+// Returns the only positional argument.
+func singleArg(flags *flag.FlagSet) (string, error) {
+	if flags.NArg() != 1 {
+		return "", usagef(flags.Name(), "exactly one argument is required")
+	}
+	return flags.Arg(0), nil
+}
+
+func envOr(key, value string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return value
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/nikolaev-dev/sdk/api"
+	"github.com/nikolaev-dev/sdk/api/apitest"
+	"github.com/nikolaev-dev/sdk/transaction"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	privateKey = "07bc17abdcee8b971bb8723e36fe9d2523306d5ab2d683631693238e0f9df142"
+	sender     = "Mx31e61a05adbd13c6b625262704bc305bf7725026"
+	recipient  = "Mx1b685a7c1e78726c48f619c497a07ed75fe00483"
+)
+
+// Run command with empty profiles file and returns exit code and stdout.
+func runCommand(t *testing.T, stdin string, args ...string) (int, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(append([]string{"-profiles", ""}, args...), strings.NewReader(stdin), &stdout, &stderr)
+	if code != 0 {
+		t.Logf("minter %s: %s", strings.Join(args, " "), stderr.String())
+	}
+	return code, stdout.String()
+}
+
+func runJSON(t *testing.T, v interface{}, stdin string, args ...string) {
+	code, stdout := runCommand(t, stdin, args...)
+	if code != 0 {
+		t.Fatalf("minter %s: exit code %d", strings.Join(args, " "), code)
+	}
+	if err := json.Unmarshal([]byte(stdout), v); err != nil {
+		t.Fatalf("minter %s: %v: %s", strings.Join(args, " "), err, stdout)
+	}
+}
+
+func TestRun_wallet(t *testing.T) {
+	var created walletResult
+	runJSON(t, &created, "", "wallet", "new", "-words", "24", "-language", "french")
+	if len(strings.Fields(created.Mnemonic)) != 24 || created.Language != "french" {
+		t.Fatalf("unexpected wallet %+v", created)
+	}
+
+	var imported walletResult
+	runJSON(t, &imported, created.Mnemonic, "wallet", "import", "-")
+	if imported != created {
+		t.Errorf("imported wallet want %+v, got %+v", created, imported)
+	}
+
+	var derived walletResult
+	runJSON(t, &derived, "", "wallet", "derive", "0x"+privateKey)
+	if derived.Address != sender {
+		t.Errorf("address want %s, got %s", sender, derived.Address)
+	}
+	var public walletResult
+	runJSON(t, &public, "", "wallet", "derive", "-public", derived.PublicKey)
+	if public.Address != sender || public.PrivateKey != "" {
+		t.Errorf("unexpected wallet %+v", public)
+	}
+
+	if code, _ := runCommand(t, "", "wallet", "new", "-words", "13"); code != 2 {
+		t.Errorf("exit code want 2, got %d", code)
+	}
+}
+
+func TestRun_tx(t *testing.T) {
+	server := apitest.NewServer(nil)
+	defer server.Close()
+	server.Ledger.SetBalance(sender, "MNT", big.NewInt(0).Exp(big.NewInt(10), big.NewInt(19), nil))
+	server.Ledger.AddCoin(api.CoinInfoResult{Symbol: "TEST"})
+
+	var built builtTx
+	runJSON(t, &built, "", "-node", server.URL, "tx", "build", "send", "-to", recipient, "-value", "1.5", "-from", sender)
+	var signed signedTx
+	runJSON(t, &signed, built.Tx, "tx", "sign", "-key", privateKey)
+	if signed.From != sender {
+		t.Errorf("sender want %s, got %s", sender, signed.From)
+	}
+
+	var decoded transaction.Description
+	runJSON(t, &decoded, "", "tx", "decode", signed.Tx)
+	data, _ := json.Marshal(decoded.Data)
+	if decoded.Hash != signed.Hash || decoded.From != sender || decoded.Nonce != "1" || decoded.GasCoin != "MNT" ||
+		string(data) != `{"coin":"MNT","to":"`+recipient+`","value":"1500000000000000000"}` {
+		t.Errorf("unexpected decoded transaction %+v %s", decoded, data)
+	}
+
+	var sent struct {
+		Hash string `json:"hash"`
+	}
+	runJSON(t, &sent, "", "-node", server.URL, "tx", "send", signed.Tx)
+	if "Mt"+strings.ToLower(sent.Hash) != signed.Hash {
+		t.Errorf("hash want %s, got %s", signed.Hash, sent.Hash)
+	}
+	if got := server.Ledger.Balance(recipient, "MNT"); got.String() != "1500000000000000000" {
+		t.Errorf("recipient balance %s", got)
+	}
+
+	var address struct {
+		TransactionCount string `json:"transaction_count"`
+	}
+	runJSON(t, &address, "", "-node", server.URL, "address", sender)
+	if address.TransactionCount != "1" {
+		t.Errorf("transaction count want 1, got %s", address.TransactionCount)
+	}
+	var block struct {
+		NumTxs string `json:"num_txs"`
+	}
+	runJSON(t, &block, "", "-node", server.URL, "block", "2")
+	if block.NumTxs != "1" {
+		t.Errorf("block transactions want 1, got %s", block.NumTxs)
+	}
+	var estimate struct {
+		WillGet string `json:"will_get"`
+	}
+	runJSON(t, &estimate, "", "-node", server.URL, "estimate", "sell", "-sell", "mnt", "-buy", "TEST", "-value", "0.5")
+	if estimate.WillGet == "" {
+		t.Error("estimate want result")
+	}
+
+	if code, _ := runCommand(t, "", "-node", server.URL, "tx", "send", signed.Tx); code != 1 {
+		t.Errorf("send of the same transaction exit code want 1, got %d", code)
+	}
+}
+
+func TestLoadProfiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "profiles.json")
+	err = ioutil.WriteFile(file, []byte(`{"testnet": {"node": "http://node:8841"}, "custom": {"node": "http://custom", "chain_id": 7}}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := LoadProfiles(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := profiles["testnet"]; p.Node != "http://node:8841" || p.ChainID != builtinProfiles()["testnet"].ChainID {
+		t.Errorf("unexpected testnet profile %+v", p)
+	}
+	if p := profiles["custom"]; p.ChainID != 7 || p.GasCoin != "MNT" {
+		t.Errorf("unexpected custom profile %+v", p)
+	}
+
+	if _, err := LoadProfiles(filepath.Join(dir, "missing.json")); err != nil {
+		t.Errorf("missing file want built-in profiles, got %v", err)
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	for s, want := range map[string]string{
+		"1":                     "1000000000000000000",
+		"1.5":                   "1500000000000000000",
+		".25":                   "250000000000000000",
+		"0.000000000000000001":  "1",
+		"":                      "",
+		"-1":                    "",
+		"1.0000000000000000001": "",
+		"1e3":                   "",
+	} {
+		got, err := parseAmount(s)
+		if want == "" {
+			if err == nil {
+				t.Errorf("%q want error, got %s", s, got)
+			}
+			continue
+		}
+		if err != nil || got.String() != want {
+			t.Errorf("%q want %s, got %s, %v", s, want, got, err)
+		}
+	}
+}
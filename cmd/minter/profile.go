@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/nikolaev-dev/sdk/transaction"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Network profile: node to query and parameters of built transactions.
+type Profile struct {
+	Node    string              `json:"node"`
+	ChainID transaction.ChainID `json:"chain_id"`
+	GasCoin string              `json:"gas_coin"`
+}
+
+// Built-in profiles, the profiles file adds new ones and overrides these by name.
+func builtinProfiles() map[string]*Profile {
+	return map[string]*Profile{
+		"mainnet": {Node: "https://api.minter.one", ChainID: transaction.MainNetChainID, GasCoin: "BIP"},
+		"testnet": {Node: "https://minter-node-1.testnet.minter.network:8841", ChainID: transaction.TestNetChainID, GasCoin: "MNT"},
+		"local":   {Node: "http://localhost:8841", ChainID: transaction.TestNetChainID, GasCoin: "MNT"},
+	}
+}
+
+// Returns "~/.minter/profiles.json" or empty string if home directory is unknown.
+func defaultProfilesFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".minter", "profiles.json")
+}
+
+// Load profiles from JSON file {"name": {"node": "...", "chain_id": 1, "gas_coin": "MNT"}} over built-in ones.
+// Missing file is not an error, empty fields of profile overriding built-in one are taken from it.
+func LoadProfiles(file string) (map[string]*Profile, error) {
+	profiles := builtinProfiles()
+	if file == "" {
+		return profiles, nil
+	}
+	b, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var loaded map[string]*Profile
+	if err := json.Unmarshal(b, &loaded); err != nil {
+		return nil, fmt.Errorf("profiles %s: %w", file, err)
+	}
+	for name, profile := range loaded {
+		if builtin, ok := profiles[name]; ok {
+			if profile.Node == "" {
+				profile.Node = builtin.Node
+			}
+			if profile.ChainID == 0 {
+				profile.ChainID = builtin.ChainID
+			}
+			if profile.GasCoin == "" {
+				profile.GasCoin = builtin.GasCoin
+			}
+		}
+		if profile.ChainID == 0 {
+			return nil, fmt.Errorf("profiles %s: chain_id of %q is not set", file, name)
+		}
+		if profile.GasCoin == "" {
+			profile.GasCoin = "MNT"
+		}
+		profiles[name] = profile
+	}
+	return profiles, nil
+}
+
+func runProfiles(e *env, args []string) error {
+	flags := newFlagSet(e, "profiles")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	return e.print(e.profiles)
+}
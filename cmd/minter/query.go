@@ -0,0 +1,130 @@
+package main
+
+import (
+	"github.com/nikolaev-dev/sdk/api"
+	"strconv"
+	"strings"
+)
+
+func runAddress(e *env, args []string) error {
+	flags := newFlagSet(e, commands["address"].usage)
+	height := flags.Int("height", api.LatestBlockHeight, "block height, the latest block by default")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	address, err := singleArg(flags)
+	if err != nil {
+		return err
+	}
+	result, err := e.api().AddressAtHeight(address, *height)
+	if err != nil {
+		return err
+	}
+	return e.print(result)
+}
+
+func runBlock(e *env, args []string) error {
+	flags := newFlagSet(e, commands["block"].usage)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	arg, err := singleArg(flags)
+	if err != nil {
+		return err
+	}
+	height, err := strconv.Atoi(arg)
+	if err != nil || height <= 0 {
+		return usagef(flags.Name(), "invalid height %q", arg)
+	}
+	result, err := e.api().Block(height)
+	if err != nil {
+		return err
+	}
+	return e.print(result)
+}
+
+func runCandidate(e *env, args []string) error {
+	flags := newFlagSet(e, commands["candidate"].usage)
+	height := flags.Int("height", api.LatestBlockHeight, "block height, the latest block by default")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	pubKey, err := singleArg(flags)
+	if err != nil {
+		return err
+	}
+	result, err := e.api().CandidateAtHeight(pubKey, *height)
+	if err != nil {
+		return err
+	}
+	return e.print(result)
+}
+
+func runEstimate(e *env, args []string) error {
+	return runSubcommand(e, commands["estimate"].usage, args, map[string]func(e *env, args []string) error{
+		"buy":  runEstimateBuy,
+		"sell": runEstimateSell,
+		"tx":   runEstimateTx,
+	})
+}
+
+func runEstimateBuy(e *env, args []string) error {
+	flags := newFlagSet(e, "estimate buy -sell COIN -buy COIN -value 1.5")
+	coinToSell := flags.String("sell", "", "coin to sell")
+	coinToBuy := flags.String("buy", "", "coin to buy")
+	value := flags.String("value", "", "amount to buy in BIP")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	valueToBuy, err := parseAmount(*value)
+	if err != nil {
+		return usagef(flags.Name(), "%v", err)
+	}
+	result, err := e.api().EstimateCoinBuy(strings.ToUpper(*coinToSell), valueToBuy.String(), strings.ToUpper(*coinToBuy))
+	if err != nil {
+		return err
+	}
+	return e.print(result)
+}
+
+func runEstimateSell(e *env, args []string) error {
+	flags := newFlagSet(e, "estimate sell -sell COIN -buy COIN -value 1.5")
+	coinToSell := flags.String("sell", "", "coin to sell")
+	coinToBuy := flags.String("buy", "", "coin to buy")
+	value := flags.String("value", "", "amount to sell in BIP")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	valueToSell, err := parseAmount(*value)
+	if err != nil {
+		return usagef(flags.Name(), "%v", err)
+	}
+	result, err := e.api().EstimateCoinSell(strings.ToUpper(*coinToSell), valueToSell.String(), strings.ToUpper(*coinToBuy))
+	if err != nil {
+		return err
+	}
+	return e.print(result)
+}
+
+// Raw transaction passed to api as is.
+type rawTx string
+
+func (t rawTx) Encode() (string, error) {
+	return string(t), nil
+}
+
+func runEstimateTx(e *env, args []string) error {
+	flags := newFlagSet(e, "estimate tx <tx | ->")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	raw, err := argOrStdin(e, flags)
+	if err != nil {
+		return err
+	}
+	result, err := e.api().EstimateTxCommission(rawTx(withPrefix(raw)))
+	if err != nil {
+		return err
+	}
+	return e.print(result)
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/nikolaev-dev/sdk/transaction"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Builder of transaction data of one type from its flags.
+type dataBuilder struct {
+	usage string
+	// Define flags of the type in flag set, returned function is called after parsing to build data.
+	flags func(flags *flag.FlagSet) func() (transaction.DataInterface, error)
+}
+
+var dataBuilders = map[string]*dataBuilder{
+	"send": {
+		usage: "-to Mx... -coin MNT -value 1.5",
+		flags: func(flags *flag.FlagSet) func() (transaction.DataInterface, error) {
+			to := flags.String("to", "", "recipient address")
+			coin := flags.String("coin", "", "coin to send, gas coin of the profile by default")
+			value := flags.String("value", "", "amount to send in BIP, e.g. 1.5")
+			return func() (transaction.DataInterface, error) {
+				amount, err := parseAmount(*value)
+				if err != nil {
+					return nil, err
+				}
+				data, err := transaction.NewSendData().SetCoin(strings.ToUpper(*coin)).SetValue(amount).SetTo(*to)
+				if err != nil {
+					return nil, fmt.Errorf("to: %w", err)
+				}
+				return data, nil
+			}
+		},
+	},
+}
+
+func runTx(e *env, args []string) error {
+	return runSubcommand(e, commands["tx"].usage, args, map[string]func(e *env, args []string) error{
+		"build":  runTxBuild,
+		"sign":   runTxSign,
+		"decode": runTxDecode,
+		"send":   runTxSend,
+	})
+}
+
+type builtTx struct {
+	Type string `json:"type"`
+	Tx   string `json:"tx"`
+}
+
+func runTxBuild(e *env, args []string) error {
+	types := make([]string, 0, len(dataBuilders))
+	for name := range dataBuilders {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	usage := "tx build " + strings.Join(types, "|") + " [flags]"
+	if len(args) == 0 {
+		return usagef(usage, "transaction type is required")
+	}
+	builder, ok := dataBuilders[args[0]]
+	if !ok {
+		return usagef(usage, "unknown transaction type %q", args[0])
+	}
+
+	flags := newFlagSet(e, "tx build "+args[0]+" "+builder.usage+" [-nonce N | -from Mx...] [-gas-price 1] [-gas-coin MNT] [-payload text] [-multisig]")
+	buildData := builder.flags(flags)
+	nonce := flags.Uint64("nonce", 0, "nonce of transaction")
+	from := flags.String("from", "", "sender address to get nonce from node if -nonce is not set")
+	gasPrice := flags.Uint("gas-price", 1, "gas price")
+	gasCoin := flags.String("gas-coin", "", "coin to pay commission, gas coin of the profile by default")
+	payload := flags.String("payload", "", "arbitrary text attached to transaction")
+	multisig := flags.Bool("multisig", false, "transaction is signed by multisig address")
+	if err := parseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return usagef(flags.Name(), "unexpected arguments")
+	}
+	if *gasPrice == 0 || *gasPrice > 255 {
+		return usagef(flags.Name(), "gas price must be in range [1, 255]")
+	}
+	if *gasCoin == "" {
+		*gasCoin = e.profile.GasCoin
+	}
+	if coin := flags.Lookup("coin"); coin != nil && coin.Value.String() == "" {
+		_ = coin.Value.Set(e.profile.GasCoin)
+	}
+	if *nonce == 0 {
+		if *from == "" {
+			return usagef(flags.Name(), "-nonce or -from is required")
+		}
+		n, err := e.api().Nonce(*from)
+		if err != nil {
+			return err
+		}
+		*nonce = n
+	}
+
+	data, err := buildData()
+	if err != nil {
+		return usagef(flags.Name(), "%v", err)
+	}
+	tx, err := transaction.NewBuilder(e.profile.ChainID).NewTransaction(data)
+	if err != nil {
+		return err
+	}
+	tx.SetNonce(*nonce).SetGasPrice(uint8(*gasPrice)).SetGasCoin(strings.ToUpper(*gasCoin)).SetPayload([]byte(*payload))
+	if *multisig {
+		tx.SetMultiSignatureType()
+	}
+	encoded, err := tx.Encode()
+	if err != nil {
+		return err
+	}
+	return e.print(&builtTx{Type: args[0], Tx: encoded})
+}
+
+// Repeatable string flag.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+type signedTx struct {
+	Tx   string `json:"tx"`
+	Hash string `json:"hash"`
+	From string `json:"from"`
+}
+
+func runTxSign(e *env, args []string) error {
+	flags := newFlagSet(e, "tx sign [-key K]... [-multisig Mx...] <tx | ->")
+	var keys stringsFlag
+	flags.Var(&keys, "key", "private key, repeat for multisig transaction; MINTER_PRIVATE_KEY by default")
+	multisig := flags.String("multisig", "", "multisig address, required for the first signatures of multisig transaction")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	raw, err := argOrStdin(e, flags)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		if key := os.Getenv("MINTER_PRIVATE_KEY"); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return usagef(flags.Name(), "-key or MINTER_PRIVATE_KEY is required")
+	}
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, "0x")
+	}
+
+	tx, err := transaction.Decode(raw)
+	if err != nil {
+		return err
+	}
+	var signed transaction.SignedTransaction
+	switch tx.GetTransaction().SignatureType {
+	case transaction.SignatureTypeSingle:
+		if len(keys) != 1 {
+			return usagef(flags.Name(), "single signature transaction is signed with one key")
+		}
+		signed, err = tx.Sign(keys[0])
+	case transaction.SignatureTypeMulti:
+		if *multisig == "" && len(tx.SignatureData()) == 0 {
+			return usagef(flags.Name(), "-multisig is required for the first signatures")
+		}
+		signed, err = tx.Sign(*multisig, keys...)
+	default:
+		err = fmt.Errorf("unknown signature type %d", tx.GetTransaction().SignatureType)
+	}
+	if err != nil {
+		return err
+	}
+
+	encoded, err := signed.Encode()
+	if err != nil {
+		return err
+	}
+	hash, err := signed.Hash()
+	if err != nil {
+		return err
+	}
+	from, err := signed.SenderAddress()
+	if err != nil {
+		return err
+	}
+	return e.print(&signedTx{Tx: encoded, Hash: hash, From: from})
+}
+
+func runTxDecode(e *env, args []string) error {
+	flags := newFlagSet(e, "tx decode <tx | ->")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	raw, err := argOrStdin(e, flags)
+	if err != nil {
+		return err
+	}
+	tx, err := transaction.Decode(raw)
+	if err != nil {
+		return err
+	}
+
+	result, err := transaction.Describe(tx)
+	if err != nil {
+		return err
+	}
+	return e.print(result)
+}
+
+func runTxSend(e *env, args []string) error {
+	flags := newFlagSet(e, "tx send <tx | ->")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	raw, err := argOrStdin(e, flags)
+	if err != nil {
+		return err
+	}
+	if _, err := transaction.Decode(raw); err != nil {
+		return err
+	}
+	result, err := e.api().SendRawTransaction(withPrefix(raw))
+	if err != nil {
+		return err
+	}
+	return e.print(result)
+}
+
+// Returns raw transaction with "0x" prefix.
+func withPrefix(raw string) string {
+	if strings.HasPrefix(raw, "0x") || strings.HasPrefix(raw, "0X") {
+		return "0x" + raw[2:]
+	}
+	return "0x" + raw
+}
+
+var errInvalidAmount = errors.New("invalid amount")
+
+// Parse decimal amount in BIP with up to 18 fractional digits to pip.
+func parseAmount(s string) (*big.Int, error) {
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if whole == "" && frac == "" || len(frac) > 18 || strings.ContainsAny(whole+frac, "+-") {
+		return nil, fmt.Errorf("%w %q", errInvalidAmount, s)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	pip, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", 18-len(frac)), 10)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", errInvalidAmount, s)
+	}
+	return pip, nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/nikolaev-dev/sdk/wallet"
+)
+
+type walletResult struct {
+	Mnemonic   string `json:"mnemonic,omitempty"`
+	Language   string `json:"language,omitempty"`
+	Seed       string `json:"seed,omitempty"`
+	PrivateKey string `json:"private_key,omitempty"`
+	PublicKey  string `json:"public_key"`
+	Address    string `json:"address"`
+}
+
+func runWallet(e *env, args []string) error {
+	return runSubcommand(e, commands["wallet"].usage, args, map[string]func(e *env, args []string) error{
+		"new":    runWalletNew,
+		"import": runWalletImport,
+		"derive": runWalletDerive,
+	})
+}
+
+func runWalletNew(e *env, args []string) error {
+	flags := newFlagSet(e, "wallet new [-words 12] [-language english] [-passphrase P]")
+	words := flags.Int("words", 12, "number of mnemonic words: 12, 15, 18, 21 or 24")
+	languageName := flags.String("language", wallet.English.String(), "mnemonic wordlist language")
+	passphrase := flags.String("passphrase", "", "BIP39 passphrase")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return usagef(flags.Name(), "unexpected arguments")
+	}
+	language, err := parseLanguage(*languageName)
+	if err != nil {
+		return usagef(flags.Name(), "%v", err)
+	}
+
+	mnemonic, err := wallet.NewMnemonicWithEntropy(wallet.EntropySize(*words*32/3), language)
+	if err == wallet.ErrEntropySize {
+		return usagef(flags.Name(), "invalid number of words %d", *words)
+	}
+	if err != nil {
+		return err
+	}
+	return printWallet(e, mnemonic, *passphrase)
+}
+
+func runWalletImport(e *env, args []string) error {
+	flags := newFlagSet(e, "wallet import [-passphrase P] <mnemonic | ->")
+	passphrase := flags.String("passphrase", "", "BIP39 passphrase")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	mnemonic, err := argOrStdin(e, flags)
+	if err != nil {
+		return err
+	}
+	return printWallet(e, mnemonic, *passphrase)
+}
+
+func printWallet(e *env, mnemonic, passphrase string) error {
+	language, err := wallet.MnemonicLanguage(mnemonic)
+	if err != nil {
+		return err
+	}
+	seed, err := wallet.SeedWithPassphrase(mnemonic, passphrase)
+	if err != nil {
+		return err
+	}
+	w, err := wallet.NewWallet(seed)
+	if err != nil {
+		return err
+	}
+	return e.print(&walletResult{
+		Mnemonic:   mnemonic,
+		Language:   language.String(),
+		Seed:       hex.EncodeToString(seed),
+		PrivateKey: w.PrivateKey(),
+		PublicKey:  w.PublicKey(),
+		Address:    w.Address(),
+	})
+}
+
+func runWalletDerive(e *env, args []string) error {
+	flags := newFlagSet(e, "wallet derive [-public] <private key | public key | ->")
+	public := flags.Bool("public", false, "argument is public key, derive address only")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	key, err := argOrStdin(e, flags)
+	if err != nil {
+		return err
+	}
+
+	if *public {
+		publicKey, err := wallet.ParsePublicKey(key)
+		if err != nil {
+			return err
+		}
+		return e.print(&walletResult{PublicKey: publicKey.String(), Address: publicKey.Address().String()})
+	}
+	privateKey, err := wallet.ParsePrivateKey(key)
+	if err != nil {
+		return err
+	}
+	defer privateKey.Zero()
+	return e.print(&walletResult{
+		PrivateKey: privateKey.String(),
+		PublicKey:  privateKey.PublicKey().String(),
+		Address:    privateKey.Address().String(),
+	})
+}
+
+func parseLanguage(name string) (wallet.Language, error) {
+	for language := wallet.English; language <= wallet.Spanish; language++ {
+		if language.String() == name {
+			return language, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown language %q", name)
+}
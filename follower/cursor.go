@@ -0,0 +1,105 @@
+package follower
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Position of the last processed block.
+type Cursor struct {
+	Height int    `json:"height"`
+	Hash   string `json:"hash"`
+}
+
+// Storage of the cursor, Load returns nil cursor if nothing is saved yet.
+type CursorStore interface {
+	Load() (*Cursor, error)
+	Save(cursor *Cursor) error
+}
+
+// Cursor store in memory, it is lost on restart.
+type MemoryStore struct {
+	mu     sync.Mutex
+	cursor *Cursor
+}
+
+// Create in-memory cursor store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Load() (*Cursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cursor == nil {
+		return nil, nil
+	}
+	cursor := *s.cursor
+	return &cursor, nil
+}
+
+func (s *MemoryStore) Save(cursor *Cursor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := *cursor
+	s.cursor = &c
+	return nil
+}
+
+// Cursor store in JSON file. The file is replaced atomically, so it holds either previous or new cursor after crash.
+type FileStore struct {
+	path string
+}
+
+// Create cursor store in file by path, the file is created on first save.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (s *FileStore) Load() (*Cursor, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cursor := new(Cursor)
+	err = json.Unmarshal(data, cursor)
+	if err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
+
+func (s *FileStore) Save(cursor *Cursor) error {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	err = os.Rename(file.Name(), s.path)
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+// Package follower walks blocks of the chain from a start height to the head and then tails new blocks,
+// calling hooks for every block, transaction and event in order of heights.
+package follower
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nikolaev-dev/sdk/api"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Saved cursor points to a block with other hash, the node belongs to another chain or its state was reset.
+var ErrCursorMismatch = errors.New("block hash at cursor height does not match the saved cursor")
+
+// Hooks are called sequentially in order of heights. Error returned by a hook stops the follower,
+// the cursor is not advanced, so the block is processed again after restart.
+type (
+	BlockHandler       func(ctx context.Context, block *api.BlockResult) error
+	TransactionHandler func(ctx context.Context, block *api.BlockResult, transaction *api.TransactionResult) error
+	EventHandler       func(ctx context.Context, block *api.BlockResult, event *api.Event) error
+)
+
+// Option configures the follower created by New.
+type Option func(*Follower)
+
+// Set height of the first block to process if the store has no cursor, 1 by default.
+func WithStart(height int) Option {
+	return func(f *Follower) {
+		f.start = height
+	}
+}
+
+// Set number of blocks fetched in parallel while catching up with the head, 1 by default.
+func WithConcurrency(n int) Option {
+	return func(f *Follower) {
+		f.concurrency = n
+	}
+}
+
+// Set interval of polling for new blocks and of retries of failed requests, 1s by default.
+func WithPollInterval(interval time.Duration) Option {
+	return func(f *Follower) {
+		f.pollInterval = interval
+	}
+}
+
+// Set cursor store, in-memory store by default.
+func WithStore(store CursorStore) Option {
+	return func(f *Follower) {
+		f.store = store
+	}
+}
+
+// Set handler of node request errors, the requests are retried.
+func WithErrorHandler(handler func(error)) Option {
+	return func(f *Follower) {
+		f.onError = handler
+	}
+}
+
+// Add hook called for every block.
+func OnBlock(handler BlockHandler) Option {
+	return func(f *Follower) {
+		f.onBlock = append(f.onBlock, handler)
+	}
+}
+
+// Add hook called for every transaction of block after block hooks.
+func OnTransaction(handler TransactionHandler) Option {
+	return func(f *Follower) {
+		f.onTransaction = append(f.onTransaction, handler)
+	}
+}
+
+// Add hook called for every event of block after transaction hooks. Events are requested only if there is an event hook.
+func OnEvent(handler EventHandler) Option {
+	return func(f *Follower) {
+		f.onEvent = append(f.onEvent, handler)
+	}
+}
+
+// Follower of blocks.
+type Follower struct {
+	node          api.NodeClient
+	start         int
+	concurrency   int
+	pollInterval  time.Duration
+	store         CursorStore
+	onError       func(error)
+	onBlock       []BlockHandler
+	onTransaction []TransactionHandler
+	onEvent       []EventHandler
+}
+
+// Create follower of the node blocks.
+func New(node api.NodeClient, opts ...Option) *Follower {
+	f := &Follower{
+		node:         node,
+		start:        1,
+		concurrency:  1,
+		pollInterval: time.Second,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if f.concurrency < 1 {
+		f.concurrency = 1
+	}
+	if f.store == nil {
+		f.store = NewMemoryStore()
+	}
+	return f
+}
+
+// Follow blocks from the saved cursor or the start height until the context is done or a hook returns error.
+func (f *Follower) Run(ctx context.Context) error {
+	cursor, err := f.store.Load()
+	if err != nil {
+		return err
+	}
+	next := f.start
+	if cursor != nil {
+		if cursor.Hash != "" {
+			block, err := f.node.Block(cursor.Height)
+			if err != nil {
+				return err
+			}
+			if block.Hash != cursor.Hash {
+				return fmt.Errorf("%w: height %d, saved %s, node %s", ErrCursorMismatch, cursor.Height, cursor.Hash, block.Hash)
+			}
+		}
+		next = cursor.Height + 1
+	}
+
+	for {
+		head, err := f.head()
+		if err != nil {
+			f.reportError(err)
+		} else if next <= head {
+			err = f.catchUp(ctx, next, head)
+			if err != nil {
+				return err
+			}
+			next = head + 1
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(f.pollInterval):
+		}
+	}
+}
+
+func (f *Follower) head() (int, error) {
+	status, err := f.node.Status()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(status.LatestBlockHeight)
+}
+
+type fetchedBlock struct {
+	height int
+	block  *api.BlockResult
+	events []api.Event
+}
+
+type fetchJob struct {
+	height int
+	result chan *fetchedBlock
+}
+
+// Fetch blocks from..to in parallel and handle them in order.
+func (f *Follower) catchUp(ctx context.Context, from, to int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	// results in order of heights, the buffer limits number of blocks fetched ahead
+	results := make(chan chan *fetchedBlock, 2*f.concurrency)
+	jobs := make(chan fetchJob)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(results)
+		defer close(jobs)
+		for height := from; height <= to; height++ {
+			job := fetchJob{height: height, result: make(chan *fetchedBlock, 1)}
+			select {
+			case <-ctx.Done():
+				return
+			case results <- job.result:
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- job:
+			}
+		}
+	}()
+
+	for i := 0; i < f.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				job.result <- f.fetch(ctx, job.height)
+			}
+		}()
+	}
+
+	for result := range results {
+		var fetched *fetchedBlock
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case fetched = <-result:
+		}
+		if fetched == nil {
+			return ctx.Err()
+		}
+		err := f.handle(ctx, fetched)
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// Fetch block and its events retrying on errors, returns nil if the context is done.
+func (f *Follower) fetch(ctx context.Context, height int) *fetchedBlock {
+	for {
+		block, err := f.node.Block(height)
+		var events []api.Event
+		if err == nil && len(f.onEvent) != 0 {
+			var result *api.EventsResult
+			result, err = f.node.EventsAtHeight(height)
+			if err == nil {
+				events = result.Events
+			}
+		}
+		if err == nil {
+			return &fetchedBlock{height: height, block: block, events: events}
+		}
+		f.reportError(fmt.Errorf("block %d: %w", height, err))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(f.pollInterval):
+		}
+	}
+}
+
+func (f *Follower) handle(ctx context.Context, fetched *fetchedBlock) error {
+	block := fetched.block
+	for _, handler := range f.onBlock {
+		if err := handler(ctx, block); err != nil {
+			return err
+		}
+	}
+	if len(f.onTransaction) != 0 {
+		for i := range block.Transactions {
+			for _, handler := range f.onTransaction {
+				if err := handler(ctx, block, &block.Transactions[i]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for i := range fetched.events {
+		for _, handler := range f.onEvent {
+			if err := handler(ctx, block, &fetched.events[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return f.store.Save(&Cursor{Height: fetched.height, Hash: block.Hash})
+}
+
+func (f *Follower) reportError(err error) {
+	if f.onError != nil {
+		f.onError(err)
+	}
+}
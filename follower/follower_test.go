@@ -0,0 +1,228 @@
+package follower
+
+import (
+	"context"
+	"errors"
+	"github.com/nikolaev-dev/sdk/api"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// In-memory chain, every block has one transaction and one event.
+type fakeNode struct {
+	api.NodeClient
+
+	mu     sync.Mutex
+	head   int
+	prefix string
+	fails  map[int]int
+}
+
+func newFakeNode(head int) *fakeNode {
+	return &fakeNode{head: head, prefix: "Mh", fails: make(map[int]int)}
+}
+
+func (n *fakeNode) setHead(head int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.head = head
+}
+
+func (n *fakeNode) Status() (*api.StatusResult, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return &api.StatusResult{LatestBlockHeight: strconv.Itoa(n.head)}, nil
+}
+
+func (n *fakeNode) Block(height int) (*api.BlockResult, error) {
+	time.Sleep(time.Duration(rand.Intn(1000)) * time.Microsecond)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if height > n.head {
+		return nil, errors.New("block not found")
+	}
+	if n.fails[height] > 0 {
+		n.fails[height]--
+		return nil, errors.New("temporary error")
+	}
+	h := strconv.Itoa(height)
+	return &api.BlockResult{
+		Hash:         n.prefix + h,
+		Height:       h,
+		Transactions: []api.TransactionResult{{Hash: "Mt" + h, Height: h}},
+	}, nil
+}
+
+func (n *fakeNode) EventsAtHeight(height int) (*api.EventsResult, error) {
+	return &api.EventsResult{Events: []api.Event{{Type: "minter/RewardEvent", Value: map[string]string{"height": strconv.Itoa(height)}}}}, nil
+}
+
+type record struct {
+	mu           sync.Mutex
+	blocks       []string
+	transactions []string
+	events       []string
+}
+
+func (r *record) options() []Option {
+	return []Option{
+		OnBlock(func(ctx context.Context, block *api.BlockResult) error {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.blocks = append(r.blocks, block.Height)
+			return nil
+		}),
+		OnTransaction(func(ctx context.Context, block *api.BlockResult, transaction *api.TransactionResult) error {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.transactions = append(r.transactions, transaction.Hash)
+			return nil
+		}),
+		OnEvent(func(ctx context.Context, block *api.BlockResult, event *api.Event) error {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.events = append(r.events, event.Value["height"])
+			return nil
+		}),
+	}
+}
+
+func (r *record) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.blocks)
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition is not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFollower_Run_order(t *testing.T) {
+	node := newFakeNode(50)
+	node.fails[7] = 2
+	var r record
+	store := NewMemoryStore()
+	f := New(node, append(r.options(),
+		WithStart(3),
+		WithConcurrency(8),
+		WithPollInterval(time.Millisecond),
+		WithStore(store),
+	)...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- f.Run(ctx) }()
+
+	waitFor(t, func() bool { return r.count() == 48 })
+	node.setHead(60)
+	waitFor(t, func() bool { return r.count() == 58 })
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("Run want %v, got %v", context.Canceled, err)
+	}
+
+	for i, height := range r.blocks {
+		want := strconv.Itoa(i + 3)
+		if height != want || r.transactions[i] != "Mt"+want || r.events[i] != want {
+			t.Fatalf("position %d: want height %s, got block %s, transaction %s, event %s", i, want, height, r.transactions[i], r.events[i])
+		}
+	}
+	cursor, _ := store.Load()
+	if cursor.Height != 60 || cursor.Hash != "Mh60" {
+		t.Errorf("unexpected cursor %+v", cursor)
+	}
+}
+
+func TestFollower_Run_resume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "follower")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store := NewFileStore(filepath.Join(dir, "cursor.json"))
+
+	node := newFakeNode(10)
+	stop := errors.New("stop")
+	var handled []string
+	f := New(node, WithStore(store), WithPollInterval(time.Millisecond), OnBlock(func(ctx context.Context, block *api.BlockResult) error {
+		if block.Height == "6" {
+			return stop
+		}
+		handled = append(handled, block.Height)
+		return nil
+	}))
+	if err := f.Run(context.Background()); err != stop {
+		t.Fatalf("Run want %v, got %v", stop, err)
+	}
+	cursor, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor.Height != 5 || cursor.Hash != "Mh5" {
+		t.Fatalf("unexpected cursor %+v", cursor)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f = New(node, WithStore(store), WithStart(1), WithPollInterval(time.Millisecond), OnBlock(func(ctx context.Context, block *api.BlockResult) error {
+		handled = append(handled, block.Height)
+		if block.Height == "10" {
+			cancel()
+		}
+		return nil
+	}))
+	if err := f.Run(ctx); err != context.Canceled {
+		t.Fatalf("Run want %v, got %v", context.Canceled, err)
+	}
+	for i, height := range handled {
+		if height != strconv.Itoa(i+1) {
+			t.Fatalf("handled %v", handled)
+		}
+	}
+	if len(handled) != 10 {
+		t.Fatalf("handled %v", handled)
+	}
+
+	node.prefix = "Mx"
+	if err := New(node, WithStore(store)).Run(context.Background()); !errors.Is(err, ErrCursorMismatch) {
+		t.Errorf("Run want %v, got %v", ErrCursorMismatch, err)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "follower")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store := NewFileStore(filepath.Join(dir, "cursor.json"))
+
+	cursor, err := store.Load()
+	if err != nil || cursor != nil {
+		t.Fatalf("Load of empty store got %+v, %v", cursor, err)
+	}
+	for height := 1; height <= 3; height++ {
+		if err := store.Save(&Cursor{Height: height, Hash: "Mh" + strconv.Itoa(height)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cursor, err = store.Load()
+	if err != nil || cursor.Height != 3 || cursor.Hash != "Mh3" {
+		t.Fatalf("Load got %+v, %v", cursor, err)
+	}
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("temporary files are left: %d files", len(files))
+	}
+}
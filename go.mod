@@ -4,9 +4,14 @@ go 1.14
 
 require (
 	github.com/MinterTeam/minter-go-sdk v1.1.0
+	github.com/MinterTeam/node-grpc-gateway v1.1.1
 	github.com/ethereum/go-ethereum v1.9.10
 	github.com/go-resty/resty/v2 v2.2.0
+	github.com/golang/protobuf v1.3.5
 	github.com/tyler-smith/go-bip32 v0.0.0-20170922074101-2c9cfd177564
 	github.com/tyler-smith/go-bip39 v1.0.2
 	golang.org/x/crypto v0.0.0-20190911031432-227b76d455e7
+	golang.org/x/net v0.0.0-20200222125558-5a598a2470a0
+	golang.org/x/text v0.3.2
+	google.golang.org/grpc v1.28.0
 )
@@ -0,0 +1,189 @@
+package transaction
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"strings"
+)
+
+var (
+	ErrInvalidHex           = errors.New("invalid hex")
+	ErrInvalidRLP           = errors.New("invalid RLP")
+	ErrNonCanonical         = errors.New("non-canonical RLP")
+	ErrTrailingBytes        = errors.New("trailing bytes after RLP value")
+	ErrUnknownChainID       = errors.New("unknown chain id")
+	ErrUnknownType          = errors.New("unknown transaction type")
+	ErrUnknownSignatureType = errors.New("unknown signature type")
+	ErrInvalidCoin          = errors.New("invalid coin symbol")
+	ErrInvalidSignature     = errors.New("invalid signature")
+	ErrMalleableSignature   = errors.New("signature S value is not in lower half of curve order")
+)
+
+// Error of Decode. Use errors.Is to match it with ErrInvalidHex, ErrUnknownChainID and other errors above.
+type DecodeError struct {
+	// Field of transaction with error, e.g. "chain_id" or "data", empty for errors of the whole transaction.
+	Field string
+	Err   error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Field == "" {
+		return "decode transaction: " + e.Err.Error()
+	}
+	return "decode transaction: " + e.Field + ": " + e.Err.Error()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Decode transaction from hex string with optional "0x" prefix. Input may be untrusted: it must be canonical RLP
+// without trailing bytes, chain ID, type, signature type and gas coin must be valid and signature data well-formed,
+// otherwise *DecodeError is returned. Transaction without signature data is accepted to be signed later.
+func Decode(tx string) (SignedTransaction, error) {
+	if len(tx) >= 2 && (tx[:2] == "0x" || tx[:2] == "0X") {
+		tx = tx[2:]
+	}
+	b, err := hex.DecodeString(tx)
+	if err != nil {
+		return nil, &DecodeError{Err: fmt.Errorf("%w: %v", ErrInvalidHex, err)}
+	}
+
+	transaction := new(Transaction)
+	if err := decodeRLP(b, transaction); err != nil {
+		return nil, &DecodeError{Err: err}
+	}
+
+	switch transaction.ChainID {
+	case MainNetChainID, TestNetChainID:
+	default:
+		return nil, &DecodeError{Field: "chain_id", Err: fmt.Errorf("%w %d", ErrUnknownChainID, transaction.ChainID)}
+	}
+	if !transaction.GasCoin.valid() {
+		return nil, &DecodeError{Field: "gas_coin", Err: fmt.Errorf("%w %q", ErrInvalidCoin, transaction.GasCoin[:])}
+	}
+
+	var data DataInterface
+	switch transaction.Type {
+	case TypeSend:
+		data = &SendData{}
+	default:
+		return nil, &DecodeError{Field: "type", Err: fmt.Errorf("%w %d", ErrUnknownType, transaction.Type)}
+	}
+	if err := decodeRLP(transaction.Data, data); err != nil {
+		return nil, &DecodeError{Field: "data", Err: err}
+	}
+
+	if err := decodeSignatureData(transaction); err != nil {
+		return nil, err
+	}
+
+	return &object{
+		Transaction: transaction,
+		data:        data,
+	}, nil
+}
+
+func decodeSignatureData(transaction *Transaction) error {
+	var signatures []*Signature
+	switch transaction.SignatureType {
+	case SignatureTypeSingle:
+		if len(transaction.SignatureData) == 0 {
+			return nil
+		}
+		signature := new(Signature)
+		if err := decodeRLP(transaction.SignatureData, signature); err != nil {
+			return &DecodeError{Field: "signature_data", Err: err}
+		}
+		signatures = append(signatures, signature)
+	case SignatureTypeMulti:
+		if len(transaction.SignatureData) == 0 {
+			return nil
+		}
+		signature := new(SignatureMulti)
+		if err := decodeRLP(transaction.SignatureData, signature); err != nil {
+			return &DecodeError{Field: "signature_data", Err: err}
+		}
+		signatures = signature.Signatures
+	default:
+		return &DecodeError{Field: "signature_type", Err: fmt.Errorf("%w %d", ErrUnknownSignatureType, transaction.SignatureType)}
+	}
+
+	for i, signature := range signatures {
+		if !signature.valid() {
+			return &DecodeError{Field: fmt.Sprintf("signature_data[%d]", i), Err: ErrInvalidSignature}
+		}
+	}
+	return nil
+}
+
+// Decode RLP value, which must be the only one in b and be encoded canonically.
+func decodeRLP(b []byte, v interface{}) error {
+	err := rlp.DecodeBytes(b, v)
+	if err == rlp.ErrMoreThanOneValue {
+		return ErrTrailingBytes
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "non-canonical") {
+			return fmt.Errorf("%w: %v", ErrNonCanonical, err)
+		}
+		return fmt.Errorf("%w: %v", ErrInvalidRLP, err)
+	}
+	encoded, err := rlp.EncodeToBytes(v)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRLP, err)
+	}
+	if !bytes.Equal(encoded, b) {
+		return ErrNonCanonical
+	}
+	return nil
+}
+
+// Coin symbol is 3 to 10 upper case latin letters and digits padded with zero bytes.
+func (c Coin) valid() bool {
+	n := bytes.IndexByte(c[:], 0)
+	if n == -1 {
+		n = len(c)
+	}
+	if n < 3 {
+		return false
+	}
+	for _, b := range c[:n] {
+		if (b < 'A' || b > 'Z') && (b < '0' || b > '9') {
+			return false
+		}
+	}
+	for _, b := range c[n:] {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// V is 27 or 28, R and S are in range [1, secp256k1n).
+func (s *Signature) valid() bool {
+	if s.V == nil || s.R == nil || s.S == nil || !s.V.IsUint64() {
+		return false
+	}
+	v := s.V.Uint64()
+	if v != 27 && v != 28 {
+		return false
+	}
+	return crypto.ValidateSignatureValues(byte(v-27), s.R, s.S, false)
+}
+
+// Node accepts only signatures with S in lower half of curve order, otherwise the same transaction has two valid signatures.
+func (s *Signature) canonical() error {
+	if !s.valid() {
+		return ErrInvalidSignature
+	}
+	if !crypto.ValidateSignatureValues(byte(s.V.Uint64()-27), s.R, s.S, true) {
+		return ErrMalleableSignature
+	}
+	return nil
+}
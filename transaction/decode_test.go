@@ -0,0 +1,126 @@
+package transaction
+
+import (
+	"errors"
+	"github.com/ethereum/go-ethereum/rlp"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const signedSend = "0xf8840102018a4d4e540000000000000001aae98a4d4e5400000000000000941b685a7c1e78726c48f619c497a07ed75fe00483880de0b6b3a7640000808001b845f8431ca01f36e51600baa1d89d2bee64def9ac5d88c518cdefe45e3de66a3cf9fe410de4a01bc2228dc419a97ded0efe6848de906fbe6c659092167ef0e7dcb8d15024123a"
+
+// Returns unsigned Send transaction modified by f.
+func encodeTx(t *testing.T, f func(tx *Transaction)) string {
+	data := NewSendData().SetCoin("MNT").SetValue(big.NewInt(1)).MustSetTo("Mx1b685a7c1e78726c48f619c497a07ed75fe00483")
+	dataBytes, err := data.encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := &Transaction{Nonce: 1, ChainID: TestNetChainID, GasPrice: 1, Type: TypeSend, Data: dataBytes, SignatureType: SignatureTypeSingle}
+	copy(tx.GasCoin[:], "MNT")
+	f(tx)
+	encoded, err := tx.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded
+}
+
+func mustEncode(t *testing.T, v interface{}) []byte {
+	b, err := rlp.EncodeToBytes(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestDecode(t *testing.T) {
+	for _, tx := range []string{signedSend, signedSend[2:], "0X" + strings.ToUpper(signedSend[2:]), encodeTx(t, func(*Transaction) {})} {
+		if _, err := Decode(tx); err != nil {
+			t.Errorf("Decode(%s...) got %v", tx[:10], err)
+		}
+	}
+
+	sender, err := Decode(signedSend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address, err := sender.SenderAddress(); err != nil || address != "Mx31e61a05adbd13c6b625262704bc305bf7725026" {
+		t.Errorf("SenderAddress got %s, %v", address, err)
+	}
+}
+
+func TestDecode_invalid(t *testing.T) {
+	signature := &Signature{V: big.NewInt(27), R: big.NewInt(1), S: big.NewInt(1)}
+	for name, test := range map[string]struct {
+		tx    string
+		field string
+		err   error
+	}{
+		"empty":          {"", "", ErrInvalidRLP},
+		"prefix only":    {"0x", "", ErrInvalidRLP},
+		"one char":       {"f", "", ErrInvalidHex},
+		"not hex":        {"0xzz", "", ErrInvalidHex},
+		"truncated":      {signedSend[:40], "", ErrInvalidRLP},
+		"short list":     {"0xf8", "", ErrInvalidRLP},
+		"trailing bytes": {signedSend + "00", "", ErrTrailingBytes},
+		"not list":       {"0x01", "", ErrInvalidRLP},
+		"chain id": {encodeTx(t, func(tx *Transaction) {
+			tx.ChainID = 3
+		}), "chain_id", ErrUnknownChainID},
+		"lower case gas coin": {encodeTx(t, func(tx *Transaction) {
+			tx.GasCoin = Coin{}
+			copy(tx.GasCoin[:], "mnt")
+		}), "gas_coin", ErrInvalidCoin},
+		"empty gas coin": {encodeTx(t, func(tx *Transaction) {
+			tx.GasCoin = Coin{}
+		}), "gas_coin", ErrInvalidCoin},
+		"gas coin bytes after padding": {encodeTx(t, func(tx *Transaction) {
+			tx.GasCoin[9] = 'X'
+		}), "gas_coin", ErrInvalidCoin},
+		"type": {encodeTx(t, func(tx *Transaction) {
+			tx.Type = 99
+		}), "type", ErrUnknownType},
+		"data": {encodeTx(t, func(tx *Transaction) {
+			tx.Data = []byte{0xc0}
+		}), "data", ErrInvalidRLP},
+		"data trailing bytes": {encodeTx(t, func(tx *Transaction) {
+			tx.Data = append(tx.Data, 0x80)
+		}), "data", ErrTrailingBytes},
+		"data non-canonical integer": {encodeTx(t, func(tx *Transaction) {
+			coin := Coin{'M', 'N', 'T'}
+			tx.Data = mustEncode(t, []interface{}{coin, [20]byte{}, rlp.RawValue{0x82, 0x00, 0x01}})
+		}), "data", ErrNonCanonical},
+		"signature type": {encodeTx(t, func(tx *Transaction) {
+			tx.SignatureType = 3
+		}), "signature_type", ErrUnknownSignatureType},
+		"signature data": {encodeTx(t, func(tx *Transaction) {
+			tx.SignatureData = []byte{0x01}
+		}), "signature_data", ErrInvalidRLP},
+		"signature v": {encodeTx(t, func(tx *Transaction) {
+			tx.SignatureData = mustEncode(t, &Signature{V: big.NewInt(29), R: signature.R, S: signature.S})
+		}), "signature_data[0]", ErrInvalidSignature},
+		"signature zero s": {encodeTx(t, func(tx *Transaction) {
+			tx.SignatureData = mustEncode(t, &Signature{V: signature.V, R: signature.R, S: big.NewInt(0)})
+		}), "signature_data[0]", ErrInvalidSignature},
+		"multisig signature": {encodeTx(t, func(tx *Transaction) {
+			tx.SignatureType = SignatureTypeMulti
+			tx.SignatureData = mustEncode(t, &SignatureMulti{Signatures: []*Signature{signature, {V: big.NewInt(27), R: big.NewInt(0), S: big.NewInt(1)}}})
+		}), "signature_data[1]", ErrInvalidSignature},
+	} {
+		_, err := Decode(test.tx)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: error got %v, want %v", name, err, test.err)
+			continue
+		}
+		decodeErr, ok := err.(*DecodeError)
+		if !ok {
+			t.Errorf("%s: error got %T, want *DecodeError", name, err)
+			continue
+		}
+		if decodeErr.Field != test.field {
+			t.Errorf("%s: field got %q, want %q", name, decodeErr.Field, test.field)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package transaction
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Human-readable view of transaction in the shape of api.TransactionResult returned by node,
+// so decoded transaction can be compared with its on-chain view. Amounts of data are in pip like the node returns,
+// Fee is added in BIP.
+type Description struct {
+	Hash     string `json:"hash"`
+	RawTx    string `json:"raw_tx"`
+	From     string `json:"from"`
+	Nonce    string `json:"nonce"`
+	Gas      string `json:"gas"`
+	GasPrice int    `json:"gas_price"`
+	GasCoin  string `json:"gas_coin"`
+	Type     int    `json:"type"`
+	// Data of transaction type with Mx addresses, coin symbols and pip amounts, e.g. {"coin", "to", "value"} of Send.
+	Data        interface{} `json:"data"`
+	Payload     []byte      `json:"payload"`
+	ServiceData []byte      `json:"service_data"`
+
+	// Fields absent in node view.
+	ChainID       int `json:"chain_id"`
+	SignatureType int `json:"signature_type"`
+	// Commission in BIP, i.e. gas multiplied by gas price.
+	Fee string `json:"fee"`
+}
+
+// Describe transaction. Sender is empty if transaction is not signed yet.
+func Describe(tx SignedTransaction) (*Description, error) {
+	encoded, err := tx.Encode()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := tx.Hash()
+	if err != nil {
+		return nil, err
+	}
+	var from string
+	if len(tx.SignatureData()) != 0 {
+		from, err = tx.SenderAddress()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t := tx.GetTransaction()
+	fee := tx.Fee()
+	gas := new(big.Int).Quo(fee, big.NewInt(1000000000000000))
+	return &Description{
+		Hash:          hash,
+		RawTx:         strings.TrimPrefix(encoded, "0x"),
+		From:          from,
+		Nonce:         strconv.FormatUint(t.Nonce, 10),
+		Gas:           gas.String(),
+		GasPrice:      int(t.GasPrice),
+		GasCoin:       t.GasCoin.String(),
+		Type:          int(t.Type),
+		Data:          tx.Data().describe(),
+		Payload:       t.Payload,
+		ServiceData:   t.ServiceData,
+		ChainID:       int(t.ChainID),
+		SignatureType: int(t.SignatureType),
+		Fee:           FormatBip(new(big.Int).Mul(fee, big.NewInt(int64(t.GasPrice)))),
+	}, nil
+}
+
+// Decode transaction and returns JSON of its Description.
+func DecodeToJSON(tx string) ([]byte, error) {
+	decoded, err := Decode(tx)
+	if err != nil {
+		return nil, err
+	}
+	description, err := Describe(decoded)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(description)
+}
+
+// Format amount in pip as decimal integer, nil is "0".
+func pipString(pip *big.Int) string {
+	if pip == nil {
+		return "0"
+	}
+	return pip.String()
+}
+
+// Format amount in pip as decimal BIP without trailing zeros, e.g. "1.5".
+func FormatBip(pip *big.Int) string {
+	if pip == nil {
+		return "0"
+	}
+	sign := ""
+	abs := new(big.Int).Set(pip)
+	if abs.Sign() < 0 {
+		sign = "-"
+		abs.Neg(abs)
+	}
+	s := abs.String()
+	if len(s) <= 18 {
+		s = strings.Repeat("0", 19-len(s)) + s
+	}
+	whole, frac := s[:len(s)-18], strings.TrimRight(s[len(s)-18:], "0")
+	if frac == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + frac
+}
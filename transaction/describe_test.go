@@ -0,0 +1,71 @@
+package transaction
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestDecodeToJSON(t *testing.T) {
+	b, err := DecodeToJSON("0xf8840102018a4d4e540000000000000001aae98a4d4e5400000000000000941b685a7c1e78726c48f619c497a07ed75fe00483880de0b6b3a7640000808001b845f8431ca01f36e51600baa1d89d2bee64def9ac5d88c518cdefe45e3de66a3cf9fe410de4a01bc2228dc419a97ded0efe6848de906fbe6c659092167ef0e7dcb8d15024123a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var description struct {
+		Description
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(b, &description); err != nil {
+		t.Fatal(err)
+	}
+	if description.Hash != "Mt13b73500c171006613fa8e82cc8b29857af1d63a34ca2cada95024bacca1670c" {
+		t.Errorf("Hash got %s", description.Hash)
+	}
+	if description.From != "Mx31e61a05adbd13c6b625262704bc305bf7725026" {
+		t.Errorf("From got %s", description.From)
+	}
+	if description.Nonce != "1" || description.Gas != "10" || description.GasPrice != 1 || description.GasCoin != "MNT" || description.Type != int(TypeSend) {
+		t.Errorf("unexpected description %+v", description.Description)
+	}
+	if description.SignatureType != int(SignatureTypeSingle) || description.Fee != "0.01" {
+		t.Errorf("SignatureType %d, Fee %s", description.SignatureType, description.Fee)
+	}
+	want := map[string]string{"coin": "MNT", "to": "Mx1b685a7c1e78726c48f619c497a07ed75fe00483", "value": "1000000000000000000"}
+	for key, value := range want {
+		if description.Data[key] != value {
+			t.Errorf("Data.%s got %s, want %s", key, description.Data[key], value)
+		}
+	}
+}
+
+func TestDescribe_unsigned(t *testing.T) {
+	data := NewSendData().SetCoin("MNT").SetValue(big.NewInt(1)).MustSetTo("Mx1b685a7c1e78726c48f619c497a07ed75fe00483")
+	tx, err := NewBuilder(TestNetChainID).NewTransaction(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	description, err := Describe(tx.SetNonce(1).SetGasPrice(1).SetGasCoin("MNT").(*object))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if description.From != "" {
+		t.Errorf("From of unsigned transaction got %s", description.From)
+	}
+}
+
+func TestFormatBip(t *testing.T) {
+	for pip, want := range map[string]string{
+		"0":                     "0",
+		"1":                     "0.000000000000000001",
+		"1500000000000000000":   "1.5",
+		"1000000000000000000":   "1",
+		"123000000000000000000": "123",
+		"-250000000000000000":   "-0.25",
+	} {
+		value, _ := new(big.Int).SetString(pip, 10)
+		if got := FormatBip(value); got != want {
+			t.Errorf("FormatBip(%s) got %s, want %s", pip, got, want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// +build go1.18
+
+package transaction
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/ethereum/go-ethereum/rlp"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// Run "go test ./transaction -fuzz FuzzDecode" to fuzz, seeds are run by "go test".
+func FuzzDecode(f *testing.F) {
+	f.Add(signedSend)
+	f.Add(signedSend[2:])
+	f.Add("0xf83e0e02018a4d4e540000000000000001aae98a4d4e540000000000000094ee81347211c72524338f9680072af90744333146880de0b6b3a764000080800180")
+	f.Add(multisigSend(f))
+	f.Add("")
+	f.Add("0xf8")
+	f.Fuzz(func(t *testing.T, tx string) {
+		decoded, err := Decode(tx)
+		if err != nil {
+			if _, ok := err.(*DecodeError); !ok {
+				t.Fatalf("error got %T %v, want *DecodeError", err, err)
+			}
+			return
+		}
+
+		// decoded transaction is canonical, so it is encoded back to the same bytes
+		encoded, err := decoded.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := strings.ToLower(tx)
+		if !strings.HasPrefix(want, "0x") {
+			want = "0x" + want
+		}
+		if encoded != want {
+			t.Fatalf("Encode got %s, want %s", encoded, want)
+		}
+
+		// accessors of decoded transaction must not panic
+		_, _ = decoded.Hash()
+		_, _ = decoded.SenderAddress()
+		_, _ = decoded.SimpleSignatureData()
+		_ = decoded.Fee()
+		if _, err := Describe(decoded); err != nil && len(decoded.SignatureData()) == 0 {
+			t.Fatalf("Describe of unsigned transaction: %v", err)
+		}
+	})
+}
+
+func FuzzDecodeSignature(f *testing.F) {
+	signature, _ := rlp.EncodeToBytes(&Signature{V: big.NewInt(27), R: big.NewInt(1), S: big.NewInt(2)})
+	multi, _ := rlp.EncodeToBytes(&SignatureMulti{Signatures: []*Signature{{V: big.NewInt(28), R: big.NewInt(3), S: big.NewInt(4)}}})
+	f.Add(signature)
+	f.Add(multi)
+	f.Add([]byte{0xc0})
+	f.Fuzz(func(t *testing.T, b []byte) {
+		if s := new(Signature); decodeRLP(b, s) == nil {
+			encoded, err := s.encode()
+			if err != nil || !bytes.Equal(encoded, b) {
+				t.Fatalf("Signature encode got %x, %v, want %x", encoded, err, b)
+			}
+			if s.valid() {
+				if sig := s.toBytes(); len(sig) != 65 || sig[64] > 1 {
+					t.Fatalf("toBytes got %x", sig)
+				}
+			}
+		}
+
+		if s := new(SignatureMulti); decodeRLP(b, s) == nil {
+			encoded, err := s.encode()
+			if err != nil || !bytes.Equal(encoded, b) {
+				t.Fatalf("SignatureMulti encode got %x, %v, want %x", encoded, err, b)
+			}
+			for _, signature := range s.Signatures {
+				if signature.valid() {
+					_ = signature.toBytes()
+				}
+			}
+		}
+
+		// decodeSignature used by AddSignature must not panic on any input
+		if s, err := decodeSignature(b); err == nil && s.valid() {
+			_ = s.toBytes()
+		}
+	})
+}
+
+// Fuzz Decode with raw bytes, the fuzzer mutates RLP structure better than hex text.
+func FuzzDecodeRLP(f *testing.F) {
+	b, _ := hex.DecodeString(signedSend[2:])
+	f.Add(b)
+	f.Fuzz(func(t *testing.T, b []byte) {
+		_, _ = Decode(hex.EncodeToString(b))
+	})
+}
+
+// Returns Send transaction of multisig address with one signature.
+func multisigSend(f *testing.F) string {
+	data := NewSendData().SetCoin("MNT").SetValue(big.NewInt(1)).MustSetTo("Mx1b685a7c1e78726c48f619c497a07ed75fe00483")
+	tx, err := NewBuilder(TestNetChainID).NewTransaction(data)
+	if err != nil {
+		f.Fatal(err)
+	}
+	signed, err := tx.SetNonce(1).SetGasPrice(1).SetGasCoin("MNT").SetMultiSignatureType().
+		Sign("Mx1b685a7c1e78726c48f619c497a07ed75fe00483", "07bc17abdcee8b971bb8723e36fe9d2523306d5ab2d683631693238e0f9df142")
+	if err != nil {
+		f.Fatal(err)
+	}
+	encoded, err := signed.Encode()
+	if err != nil {
+		f.Fatal(err)
+	}
+	return encoded
+}
@@ -0,0 +1,89 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nikolaev-dev/sdk/wallet"
+)
+
+// Member of multisig address and whether transaction has its signature.
+type MultisigMemberStatus struct {
+	MultisigMember
+	Signed bool `json:"signed"`
+}
+
+// Progress of signing multisig transaction.
+type MultisigStatus struct {
+	Multisig  wallet.Address         `json:"multisig"`
+	Threshold uint                   `json:"threshold"`
+	Members   []MultisigMemberStatus `json:"members"`
+	// Total weight of members, who have signed.
+	Weight uint `json:"weight"`
+	// Signers, who are not members of multisig, members, who have signed more than once,
+	// and signers of malleable signatures with S in upper half of curve order, their weight is not counted.
+	// Node rejects transaction with them.
+	Unknown    []wallet.Address `json:"unknown,omitempty"`
+	Duplicates []wallet.Address `json:"duplicates,omitempty"`
+	Invalid    []wallet.Address `json:"invalid,omitempty"`
+	// Weight reaches threshold and there are no unknown, duplicate or invalid signers.
+	Executable bool `json:"executable"`
+}
+
+// Recover signers of multisig transaction and check them against threshold and members of multisig,
+// e.g. taken from data of CreateMultisig transaction. Members are reported in the given order.
+func CheckMultisig(tx SignedTransaction, threshold uint, members []MultisigMember) (*MultisigStatus, error) {
+	o, err := decodeSigned(tx)
+	if err != nil {
+		return nil, err
+	}
+	if o.SignatureType != SignatureTypeMulti {
+		return nil, ErrNotMultisig
+	}
+	signature, err := o.Signature()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := o.signingHash()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &MultisigStatus{
+		Multisig:  signature.(*SignatureMulti).Multisig,
+		Threshold: threshold,
+		Members:   make([]MultisigMemberStatus, 0, len(members)),
+	}
+	index := make(map[wallet.Address]int, len(members))
+	for i, member := range members {
+		index[member.Address] = i
+		status.Members = append(status.Members, MultisigMemberStatus{MultisigMember: member})
+	}
+	for i, s := range signature.(*SignatureMulti).Signatures {
+		canonical := s.canonical()
+		if canonical != nil && !errors.Is(canonical, ErrMalleableSignature) {
+			return nil, fmt.Errorf("signature #%d: %w", i, canonical)
+		}
+		signer, err := recoverSigner(hash, s)
+		if err != nil {
+			return nil, fmt.Errorf("signature #%d: %w", i, err)
+		}
+		address, err := wallet.ParseAddress(signer)
+		if err != nil {
+			return nil, err
+		}
+		j, ok := index[address]
+		switch {
+		case canonical != nil:
+			status.Invalid = append(status.Invalid, address)
+		case !ok:
+			status.Unknown = append(status.Unknown, address)
+		case status.Members[j].Signed:
+			status.Duplicates = append(status.Duplicates, address)
+		default:
+			status.Members[j].Signed = true
+			status.Weight += status.Members[j].Weight
+		}
+	}
+	status.Executable = threshold > 0 && status.Weight >= threshold && len(status.Unknown) == 0 && len(status.Duplicates) == 0 && len(status.Invalid) == 0
+	return status, nil
+}
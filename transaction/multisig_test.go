@@ -0,0 +1,94 @@
+package transaction
+
+import (
+	"errors"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"testing"
+)
+
+func TestCheckMultisig(t *testing.T) {
+	keys, p := newPartiallySigned(t)
+	tx, err := p.unsigned()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed, err := tx.Sign(multisigAddress, keys[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := CheckMultisig(signed, p.Threshold, p.Members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Multisig.String() != multisigAddress || status.Weight != 2 || status.Executable {
+		t.Errorf("status with weight 2 got %s, %d, %v", status.Multisig, status.Weight, status.Executable)
+	}
+	for i, member := range status.Members {
+		if member.Address != p.Members[i].Address || member.Signed != (i == 1) {
+			t.Errorf("member #%d got %s signed %v", i, member.Address, member.Signed)
+		}
+	}
+
+	signed, err = signed.Sign(multisigAddress, keys[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err = CheckMultisig(signed, p.Threshold, p.Members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Weight != 5 || !status.Executable {
+		t.Errorf("status with weight 5 got %d, %v", status.Weight, status.Executable)
+	}
+
+	signed, err = signed.Sign(multisigAddress, keys[2], "07bc17abdcee8b971bb8723e36fe9d2523306d5ab2d683631693238e0f9df142")
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err = CheckMultisig(signed, p.Threshold, p.Members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Weight != 5 || status.Executable || len(status.Duplicates) != 1 || status.Duplicates[0] != p.Members[2].Address ||
+		len(status.Unknown) != 1 || status.Unknown[0].String() != "Mx31e61a05adbd13c6b625262704bc305bf7725026" {
+		t.Errorf("status with duplicate and unknown signers got %d, %v, %v, %v", status.Weight, status.Executable, status.Duplicates, status.Unknown)
+	}
+
+	// malleable signature of member is not counted, as the node rejects it
+	tx, err = p.unsigned()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err = tx.Sign(multisigAddress, keys[0], keys[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := signed.Signature()
+	if err != nil {
+		t.Fatal(err)
+	}
+	multi := signature.(*SignatureMulti)
+	s := multi.Signatures[1]
+	multi.Signatures[1] = &Signature{V: big.NewInt(55 - s.V.Int64()), R: s.R, S: new(big.Int).Sub(crypto.S256().Params().N, s.S)}
+	malleable, err := signed.(*object).setSignature(multi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err = CheckMultisig(malleable, p.Threshold, p.Members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Weight != 1 || status.Executable || status.Members[2].Signed || len(status.Invalid) != 1 || status.Invalid[0] != p.Members[2].Address {
+		t.Errorf("status with malleable signature got %d, %v, %v", status.Weight, status.Executable, status.Invalid)
+	}
+
+	single, err := Decode(signedSend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CheckMultisig(single, p.Threshold, p.Members); !errors.Is(err, ErrNotMultisig) {
+		t.Errorf("single signature transaction got %v, want ErrNotMultisig", err)
+	}
+}
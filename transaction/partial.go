@@ -0,0 +1,315 @@
+package transaction
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/nikolaev-dev/sdk/wallet"
+	"strings"
+)
+
+var (
+	ErrNotMultisig         = errors.New("transaction is not multisig")
+	ErrNotMember           = errors.New("signer is not a member of multisig")
+	ErrDuplicateSigner     = errors.New("member has already signed")
+	ErrThresholdNotReached = errors.New("weight of signatures is less than threshold")
+	ErrPartialMismatch     = errors.New("partially signed transactions are different")
+)
+
+// Member of multisig address with weight of its signature.
+type MultisigMember struct {
+	Address wallet.Address `json:"address"`
+	Weight  uint           `json:"weight"`
+}
+
+// Signature of multisig member.
+type MemberSignature struct {
+	Signer wallet.Address `json:"signer"`
+	// Hex of RLP encoded signature with "0x" prefix, as AddSignature accepts.
+	Signature string `json:"signature"`
+}
+
+// Multisig transaction collecting signatures of members offline. It is marshalled to JSON and passed between members,
+// who Sign it or Merge copies signed by others. Finalize returns transaction to send once threshold is reached.
+type PartiallySigned struct {
+	// Unsigned transaction of multisig signature type.
+	Tx         string            `json:"tx"`
+	Multisig   wallet.Address    `json:"multisig"`
+	Threshold  uint              `json:"threshold"`
+	Members    []MultisigMember  `json:"members"`
+	Signatures []MemberSignature `json:"signatures"`
+}
+
+// Create partially signed transaction without signatures. Signature data of tx is dropped, signature type must be multi.
+func NewPartiallySigned(tx EncodeInterface, multisig string, threshold uint, members []MultisigMember) (*PartiallySigned, error) {
+	encoded, err := tx.Encode()
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := Decode(encoded)
+	if err != nil {
+		return nil, err
+	}
+	t := decoded.GetTransaction()
+	if t.SignatureType != SignatureTypeMulti {
+		return nil, ErrNotMultisig
+	}
+	t.SignatureData = nil
+	unsigned, err := t.Encode()
+	if err != nil {
+		return nil, err
+	}
+	address, err := wallet.ParseAddress(multisig)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &PartiallySigned{
+		Tx:         unsigned,
+		Multisig:   address,
+		Threshold:  threshold,
+		Members:    append([]MultisigMember{}, members...),
+		Signatures: []MemberSignature{},
+	}
+	if err := p.Verify(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Parse partially signed transaction from JSON and verify it.
+func ParsePartiallySigned(b []byte) (*PartiallySigned, error) {
+	p := new(PartiallySigned)
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, err
+	}
+	if err := p.Verify(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Verify that transaction is unsigned multisig one, members are unique, threshold is reachable
+// and every signature is canonical one made by a member, who has not signed before.
+func (p *PartiallySigned) Verify() error {
+	tx, err := p.unsigned()
+	if err != nil {
+		return err
+	}
+	if len(p.Members) == 0 {
+		return errors.New("multisig members are not set")
+	}
+	var total uint
+	members := make(map[wallet.Address]bool, len(p.Members))
+	for _, member := range p.Members {
+		if members[member.Address] {
+			return fmt.Errorf("duplicate multisig member %s", member.Address)
+		}
+		if member.Weight == 0 {
+			return fmt.Errorf("zero weight of multisig member %s", member.Address)
+		}
+		members[member.Address] = true
+		total += member.Weight
+	}
+	if p.Threshold == 0 || p.Threshold > total {
+		return fmt.Errorf("threshold %d is out of range [1, %d]", p.Threshold, total)
+	}
+
+	hash, err := tx.signingHash()
+	if err != nil {
+		return err
+	}
+	signed := make(map[wallet.Address]bool, len(p.Signatures))
+	for _, s := range p.Signatures {
+		signature, err := decodeMemberSignature(s.Signature)
+		if err != nil {
+			return err
+		}
+		if err := signature.canonical(); err != nil {
+			return fmt.Errorf("signature of %s: %w", s.Signer, err)
+		}
+		signer, err := recoverSigner(hash, signature)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(signer, s.Signer.String()) {
+			return fmt.Errorf("%w: signature of %s is made by %s", ErrInvalidSignature, s.Signer, signer)
+		}
+		if !members[s.Signer] {
+			return fmt.Errorf("%w: %s", ErrNotMember, s.Signer)
+		}
+		if signed[s.Signer] {
+			return fmt.Errorf("%w: %s", ErrDuplicateSigner, s.Signer)
+		}
+		signed[s.Signer] = true
+	}
+	return nil
+}
+
+// Sign transaction with private key of member.
+func (p *PartiallySigned) Sign(privateKey string) error {
+	tx, err := p.unsigned()
+	if err != nil {
+		return err
+	}
+	hash, err := tx.signingHash()
+	if err != nil {
+		return err
+	}
+	s, err := signature(privateKey, hash)
+	if err != nil {
+		return err
+	}
+	return p.add(hash, s)
+}
+
+// Add RLP encoded signature of member, e.g. SimpleSignatureData of transaction signed by the member.
+func (p *PartiallySigned) AddSignature(signature []byte) error {
+	s := new(Signature)
+	if err := decodeRLP(signature, s); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	tx, err := p.unsigned()
+	if err != nil {
+		return err
+	}
+	hash, err := tx.signingHash()
+	if err != nil {
+		return err
+	}
+	return p.add(hash, s)
+}
+
+func (p *PartiallySigned) add(hash [32]byte, s *Signature) error {
+	if err := s.canonical(); err != nil {
+		return err
+	}
+	signer, err := recoverSigner(hash, s)
+	if err != nil {
+		return err
+	}
+	address, err := wallet.ParseAddress(signer)
+	if err != nil {
+		return err
+	}
+	if _, ok := p.member(address); !ok {
+		return fmt.Errorf("%w: %s", ErrNotMember, address)
+	}
+	if p.signed(address) {
+		return fmt.Errorf("%w: %s", ErrDuplicateSigner, address)
+	}
+	encoded, err := s.encode()
+	if err != nil {
+		return err
+	}
+	p.Signatures = append(p.Signatures, MemberSignature{Signer: address, Signature: "0x" + hex.EncodeToString(encoded)})
+	return nil
+}
+
+// Add signatures of other copy of the same transaction. Signatures of members, who have signed already, are skipped.
+func (p *PartiallySigned) Merge(other *PartiallySigned) error {
+	if err := other.Verify(); err != nil {
+		return err
+	}
+	if !strings.EqualFold(p.Tx, other.Tx) || p.Multisig != other.Multisig || p.Threshold != other.Threshold || len(p.Members) != len(other.Members) {
+		return ErrPartialMismatch
+	}
+	for i, member := range p.Members {
+		if other.Members[i] != member {
+			return ErrPartialMismatch
+		}
+	}
+	for _, s := range other.Signatures {
+		if !p.signed(s.Signer) {
+			p.Signatures = append(p.Signatures, s)
+		}
+	}
+	return nil
+}
+
+// Returns total weight of members, who have signed.
+func (p *PartiallySigned) Weight() uint {
+	var weight uint
+	for _, s := range p.Signatures {
+		if member, ok := p.member(s.Signer); ok {
+			weight += member.Weight
+		}
+	}
+	return weight
+}
+
+// Returns transaction with collected signatures in order of members, which is ready to be sent.
+// ErrThresholdNotReached is returned if weight of signatures is less than threshold.
+func (p *PartiallySigned) Finalize() (SignedTransaction, error) {
+	if err := p.Verify(); err != nil {
+		return nil, err
+	}
+	if weight := p.Weight(); weight < p.Threshold {
+		return nil, fmt.Errorf("%w: %d of %d", ErrThresholdNotReached, weight, p.Threshold)
+	}
+	tx, err := p.unsigned()
+	if err != nil {
+		return nil, err
+	}
+
+	signatureMulti := &SignatureMulti{Multisig: p.Multisig, Signatures: make([]*Signature, 0, len(p.Signatures))}
+	for _, member := range p.Members {
+		for _, s := range p.Signatures {
+			if s.Signer != member.Address {
+				continue
+			}
+			signature, err := decodeMemberSignature(s.Signature)
+			if err != nil {
+				return nil, err
+			}
+			signatureMulti.Signatures = append(signatureMulti.Signatures, signature)
+		}
+	}
+	return tx.setSignature(signatureMulti)
+}
+
+func (p *PartiallySigned) unsigned() (*object, error) {
+	tx, err := Decode(p.Tx)
+	if err != nil {
+		return nil, err
+	}
+	o := tx.(*object)
+	if o.SignatureType != SignatureTypeMulti {
+		return nil, ErrNotMultisig
+	}
+	if len(o.SignatureData()) != 0 {
+		return nil, errors.New("partially signed transaction has signature data")
+	}
+	return o, nil
+}
+
+func (p *PartiallySigned) member(address wallet.Address) (MultisigMember, bool) {
+	for _, member := range p.Members {
+		if member.Address == address {
+			return member, true
+		}
+	}
+	return MultisigMember{}, false
+}
+
+func (p *PartiallySigned) signed(address wallet.Address) bool {
+	for _, s := range p.Signatures {
+		if s.Signer == address {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeMemberSignature(s string) (*Signature, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	signature := new(Signature)
+	if err := decodeRLP(b, signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return signature, nil
+}
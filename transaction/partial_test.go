@@ -0,0 +1,178 @@
+package transaction
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/nikolaev-dev/sdk/wallet"
+	"math/big"
+	"testing"
+)
+
+const multisigAddress = "Mxee81347211c72524338f9680072af90744333146"
+
+// Returns private keys of members with weights 1, 2 and 3 and partially signed Send with threshold 3.
+func newPartiallySigned(t *testing.T) ([]string, *PartiallySigned) {
+	var keys []string
+	var members []MultisigMember
+	for i := 0; i < 3; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, hex.EncodeToString(crypto.FromECDSA(key)))
+		members = append(members, MultisigMember{Address: wallet.Address(crypto.PubkeyToAddress(key.PublicKey)), Weight: uint(i + 1)})
+	}
+
+	data := NewSendData().SetCoin("MNT").SetValue(big.NewInt(1)).MustSetTo("Mx1b685a7c1e78726c48f619c497a07ed75fe00483")
+	tx, err := NewBuilder(TestNetChainID).NewTransaction(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.SetNonce(1).SetGasPrice(1).SetGasCoin("MNT").SetMultiSignatureType()
+	p, err := NewPartiallySigned(tx, multisigAddress, 3, members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys, p
+}
+
+// Returns copy of p passed through JSON.
+func roundTrip(t *testing.T, p *PartiallySigned) *PartiallySigned {
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParsePartiallySigned(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestPartiallySigned_Finalize(t *testing.T) {
+	keys, p := newPartiallySigned(t)
+	first, second := roundTrip(t, p), roundTrip(t, p)
+
+	if err := first.Sign(keys[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := first.Finalize(); !errors.Is(err, ErrThresholdNotReached) {
+		t.Errorf("Finalize with weight 1 got %v, want ErrThresholdNotReached", err)
+	}
+	if err := second.Sign(keys[2]); err != nil {
+		t.Fatal(err)
+	}
+	second = roundTrip(t, second)
+
+	if err := first.Merge(second); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Merge(second); err != nil {
+		t.Fatalf("Merge of the same signatures got %v", err)
+	}
+	if weight := first.Weight(); weight != 4 || len(first.Signatures) != 2 {
+		t.Errorf("Weight got %d of %d signatures, want 4 of 2", weight, len(first.Signatures))
+	}
+
+	signed, err := first.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := signed.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender, err := decoded.SenderAddress(); err != nil || sender != multisigAddress {
+		t.Errorf("SenderAddress got %s, %v, want %s", sender, err, multisigAddress)
+	}
+	signature, err := decoded.Signature()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signatures := signature.(*SignatureMulti).Signatures
+	hash, err := decoded.(*object).signingHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, member := range []MultisigMember{p.Members[0], p.Members[2]} {
+		if signer, err := recoverSigner(hash, signatures[i]); err != nil || signer != member.Address.String() {
+			t.Errorf("signer #%d got %s, %v, want %s", i, signer, err, member.Address)
+		}
+	}
+}
+
+func TestPartiallySigned_checks(t *testing.T) {
+	keys, p := newPartiallySigned(t)
+	if err := p.Sign(keys[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Sign(keys[1]); !errors.Is(err, ErrDuplicateSigner) {
+		t.Errorf("second signature got %v, want ErrDuplicateSigner", err)
+	}
+	if err := p.Sign("07bc17abdcee8b971bb8723e36fe9d2523306d5ab2d683631693238e0f9df142"); !errors.Is(err, ErrNotMember) {
+		t.Errorf("signature of stranger got %v, want ErrNotMember", err)
+	}
+
+	// signature made by other member is not accepted for the claimed signer
+	forged := roundTrip(t, p)
+	other := roundTrip(t, p)
+	other.Signatures = nil
+	if err := other.Sign(keys[0]); err != nil {
+		t.Fatal(err)
+	}
+	forged.Signatures[0].Signature = other.Signatures[0].Signature
+	if err := forged.Verify(); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("forged signature got %v, want ErrInvalidSignature", err)
+	}
+
+	// the same signature with S = N - S is malleable, so the node rejects transaction with it
+	signature, err := decodeMemberSignature(p.Signatures[0].Signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	malleable, err := (&Signature{V: big.NewInt(55 - signature.V.Int64()), R: signature.R, S: new(big.Int).Sub(crypto.S256().Params().N, signature.S)}).encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.AddSignature(malleable); !errors.Is(err, ErrMalleableSignature) {
+		t.Errorf("malleable signature got %v, want ErrMalleableSignature", err)
+	}
+	forged.Signatures[0].Signature = "0x" + hex.EncodeToString(malleable)
+	if err := forged.Verify(); !errors.Is(err, ErrMalleableSignature) {
+		t.Errorf("Verify of malleable signature got %v, want ErrMalleableSignature", err)
+	}
+
+	// signature of other transaction is not accepted
+	tx, err := NewBuilder(TestNetChainID).NewTransaction(NewSendData().SetCoin("MNT").SetValue(big.NewInt(2)).MustSetTo(multisigAddress))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unrelated, err := NewPartiallySigned(tx.SetNonce(1).SetGasCoin("MNT").SetMultiSignatureType(), multisigAddress, 3, p.Members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unrelated.Merge(p); !errors.Is(err, ErrPartialMismatch) {
+		t.Errorf("Merge of other transaction got %v, want ErrPartialMismatch", err)
+	}
+
+	single, err := NewBuilder(TestNetChainID).NewTransaction(NewSendData().SetCoin("MNT").SetValue(big.NewInt(1)).MustSetTo(multisigAddress))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPartiallySigned(single.SetGasCoin("MNT"), multisigAddress, 1, p.Members); err != ErrNotMultisig {
+		t.Errorf("single signature transaction got %v, want ErrNotMultisig", err)
+	}
+	unsigned, err := Decode(p.Tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPartiallySigned(unsigned, multisigAddress, 7, p.Members); err == nil {
+		t.Error("unreachable threshold want error")
+	}
+}
@@ -0,0 +1,68 @@
+package transaction
+
+import (
+	"encoding/hex"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/nikolaev-dev/sdk/wallet"
+	"math/big"
+	"testing"
+	"testing/quick"
+)
+
+func TestSign_recoversSender(t *testing.T) {
+	property := func(nonce uint64, gasPrice uint8, to [20]byte, value uint64, payload []byte) bool {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		privateKey := hex.EncodeToString(crypto.FromECDSA(key))
+		sender := wallet.BytesToAddress(crypto.PubkeyToAddress(key.PublicKey))
+		if gasPrice == 0 {
+			gasPrice = 1
+		}
+
+		data := NewSendData().SetCoin("MNT").SetValue(new(big.Int).SetUint64(value))
+		data.To = to
+		tx, err := NewBuilder(TestNetChainID).NewTransaction(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signed, err := tx.SetNonce(nonce).SetGasPrice(gasPrice).SetGasCoin("MNT").SetPayload(payload).Sign(privateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoded, err := signed.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded, err := Decode(encoded)
+		if err != nil {
+			t.Logf("Decode(%s): %v", encoded, err)
+			return false
+		}
+		reencoded, err := decoded.Encode()
+		if err != nil || reencoded != encoded {
+			t.Logf("Encode got %s, %v, want %s", reencoded, err, encoded)
+			return false
+		}
+		address, err := decoded.SenderAddress()
+		if err != nil || address != sender {
+			t.Logf("SenderAddress of %s got %s, %v, want %s", encoded, address, err, sender)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSignature_toBytes_leadingZeros(t *testing.T) {
+	r := big.NewInt(0x0102)
+	s := new(big.Int).Lsh(big.NewInt(1), 240)
+	sig := (&Signature{V: big.NewInt(28), R: r, S: s}).toBytes()
+	if len(sig) != 65 || sig[30] != 0x01 || sig[31] != 0x02 || sig[33] != 0x01 || sig[64] != 1 {
+		t.Errorf("toBytes got %x", sig)
+	}
+}
@@ -52,3 +52,15 @@ func (d *SendData) encode() ([]byte, error) {
 func (d *SendData) fee() fee {
 	return feeTypeSend
 }
+
+func (d *SendData) describe() interface{} {
+	return &struct {
+		Coin  string `json:"coin"`
+		To    string `json:"to"`
+		Value string `json:"value"`
+	}{
+		Coin:  d.Coin.String(),
+		To:    wallet.BytesToAddress(d.To),
+		Value: pipString(d.Value),
+	}
+}
@@ -15,8 +15,22 @@ import (
 
 type Type byte
 
+// Types of transactions, only Send can be built and decoded by this package yet.
 const (
 	TypeSend Type = iota + 1
+	TypeSellCoin
+	TypeSellAllCoin
+	TypeBuyCoin
+	TypeCreateCoin
+	TypeDeclareCandidacy
+	TypeDelegate
+	TypeUnbond
+	TypeRedeemCheck
+	TypeSetCandidateOnline
+	TypeSetCandidateOffline
+	TypeCreateMultisig
+	TypeMultisend
+	TypeEditCandidate
 )
 
 type fee uint
@@ -35,9 +49,11 @@ const (
 
 type ChainID byte
 
+// Chain IDs of Minter networks, TestNetChainID was 1 in earlier versions of SDK,
+// which signed transactions rejected by testnet.
 const (
-	_ ChainID = iota
-	TestNetChainID
+	MainNetChainID ChainID = 1
+	TestNetChainID ChainID = 2
 )
 
 type Builder struct {
@@ -76,6 +92,8 @@ func (b *Builder) NewTransaction(data DataInterface) (Interface, error) {
 type DataInterface interface {
 	encode() ([]byte, error)
 	fee() fee
+	// Returns data in the shape of node's transaction data for Describe.
+	describe() interface{}
 }
 
 type Coin [10]byte
@@ -169,53 +187,10 @@ func (o *object) Signature() (signatureInterface, error) {
 	return signature, nil
 }
 
-// Decode transaction
-func Decode(tx string) (SignedTransaction, error) {
-	decodeString, err := hex.DecodeString(tx[2:])
-	if err != nil {
-		return nil, err
-	}
-
-	transaction := new(Transaction)
-	err = rlp.DecodeBytes(decodeString, transaction)
-	if err != nil {
-		return nil, err
-	}
-
-	var data interface{}
-	switch transaction.Type {
-	case TypeSend:
-		data = &SendData{}
-	default:
-		return nil, errors.New("unknown transaction type")
-	}
-
-	err = rlp.DecodeBytes(transaction.Data, data)
-	if err != nil {
-		return nil, err
-	}
-
-	result := &object{
-		Transaction: transaction,
-		data:        data.(DataInterface),
-	}
-	return result, nil
-}
-
 // Get sender address
 func (o *object) SenderAddress() (string, error) {
 	if o.SignatureType == SignatureTypeSingle {
-		hash, err := rlpHash([]interface{}{
-			o.Transaction.Nonce,
-			o.Transaction.ChainID,
-			o.Transaction.GasPrice,
-			o.Transaction.GasCoin,
-			o.Transaction.Type,
-			o.Transaction.Data,
-			o.Transaction.Payload,
-			o.Transaction.ServiceData,
-			o.Transaction.SignatureType,
-		})
+		hash, err := o.signingHash()
 		if err != nil {
 			return "", err
 		}
@@ -225,17 +200,7 @@ func (o *object) SenderAddress() (string, error) {
 			return "", err
 		}
 
-		ecrecover, err := crypto.Ecrecover(hash[:], signature.(*Signature).toBytes())
-		if err != nil {
-			return "", err
-		}
-
-		address, err := wallet.AddressByPublicKey(hex.EncodeToString(ecrecover))
-		if err != nil {
-			return "", err
-		}
-
-		return address, nil
+		return recoverSigner(hash, signature.(*Signature))
 	}
 
 	signature, err := o.Signature()
@@ -289,9 +254,11 @@ func decodeSignature(b []byte) (*Signature, error) {
 
 func (s *Signature) toBytes() []byte {
 	sig := make([]byte, 65)
-	copy(sig[:32], s.R.Bytes())
-	copy(sig[32:64], s.S.Bytes())
-	sig[64] = s.V.Bytes()[0] - 27
+	// R and S are big-endian, shorter values are padded with leading zeros
+	rBytes, sBytes := s.R.Bytes(), s.S.Bytes()
+	copy(sig[32-len(rBytes):32], rBytes)
+	copy(sig[64-len(sBytes):64], sBytes)
+	sig[64] = byte(s.V.Uint64() - 27)
 
 	return sig
 }
@@ -439,17 +406,7 @@ func (o *object) AddSignature(signatures ...[]byte) (SignedTransaction, error) {
 
 // sign transaction
 func (o *object) Sign(key string, multisigPrKeys ...string) (SignedTransaction, error) {
-	h, err := rlpHash([]interface{}{
-		o.Transaction.Nonce,
-		o.Transaction.ChainID,
-		o.Transaction.GasPrice,
-		o.Transaction.GasCoin,
-		o.Transaction.Type,
-		o.Transaction.Data,
-		o.Transaction.Payload,
-		o.Transaction.ServiceData,
-		o.Transaction.SignatureType,
-	})
+	h, err := o.signingHash()
 	if err != nil {
 		return nil, err
 	}
@@ -501,6 +458,35 @@ func (o *object) Sign(key string, multisigPrKeys ...string) (SignedTransaction,
 	}
 }
 
+// Returns hash of transaction fields signed by sender or members of multisig.
+func (o *object) signingHash() ([32]byte, error) {
+	return rlpHash([]interface{}{
+		o.Transaction.Nonce,
+		o.Transaction.ChainID,
+		o.Transaction.GasPrice,
+		o.Transaction.GasCoin,
+		o.Transaction.Type,
+		o.Transaction.Data,
+		o.Transaction.Payload,
+		o.Transaction.ServiceData,
+		o.Transaction.SignatureType,
+	})
+}
+
+// Returns address of signer of hash, signature is not set or was added by AddSignature without checks if it is invalid.
+func recoverSigner(hash [32]byte, signature *Signature) (string, error) {
+	if !signature.valid() {
+		return "", ErrInvalidSignature
+	}
+
+	ecrecover, err := crypto.Ecrecover(hash[:], signature.toBytes())
+	if err != nil {
+		return "", err
+	}
+
+	return wallet.AddressByPublicKey(hex.EncodeToString(ecrecover))
+}
+
 func signature(prKey string, h [32]byte) (*Signature, error) {
 	sig, err := sign(prKey, h)
 	if err != nil {
@@ -0,0 +1,120 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nikolaev-dev/sdk/wallet"
+	"strings"
+)
+
+var (
+	ErrSenderMismatch  = errors.New("transaction is not signed by expected sender")
+	ErrChainIDMismatch = errors.New("transaction chain id does not match")
+)
+
+// Verify that signature is RLP encoded Signature of hash made by address, e.g. SimpleSignatureData and SigningHash of transaction.
+// Signatures with S in upper half of curve order are rejected as malleable.
+func VerifySignature(hash []byte, signature []byte, address string) error {
+	if len(hash) != 32 {
+		return fmt.Errorf("hash length is %d, want 32", len(hash))
+	}
+	s := new(Signature)
+	if err := decodeRLP(signature, s); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	expected, err := wallet.ParseAddress(address)
+	if err != nil {
+		return err
+	}
+	var h [32]byte
+	copy(h[:], hash)
+	return verifySignature(h, s, expected)
+}
+
+func verifySignature(hash [32]byte, s *Signature, expected wallet.Address) error {
+	if err := s.canonical(); err != nil {
+		return err
+	}
+	signer, err := recoverSigner(hash, s)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(signer, expected.String()) {
+		return fmt.Errorf("%w: %s, signed by %s", ErrSenderMismatch, expected, signer)
+	}
+	return nil
+}
+
+// Returns hash of transaction, which is signed by sender or members of multisig.
+func SigningHash(tx SignedTransaction) ([]byte, error) {
+	o, err := decodeSigned(tx)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := o.signingHash()
+	if err != nil {
+		return nil, err
+	}
+	return hash[:], nil
+}
+
+// Verify that transaction has chain id and is signed by expectedSender with canonical signatures.
+// Sender of multisig transaction is multisig address, every signature of members must be valid and low-S,
+// use CheckMultisig to check members and threshold.
+func Verify(tx SignedTransaction, expectedSender string, chainID ChainID) error {
+	o, err := decodeSigned(tx)
+	if err != nil {
+		return err
+	}
+	if o.ChainID != chainID {
+		return fmt.Errorf("%w: %d, want %d", ErrChainIDMismatch, o.ChainID, chainID)
+	}
+	expected, err := wallet.ParseAddress(expectedSender)
+	if err != nil {
+		return err
+	}
+	if len(o.SignatureData()) == 0 {
+		return fmt.Errorf("%w: transaction is not signed", ErrInvalidSignature)
+	}
+	signature, err := o.Signature()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	hash, err := o.signingHash()
+	if err != nil {
+		return err
+	}
+
+	switch s := signature.(type) {
+	case *Signature:
+		return verifySignature(hash, s, expected)
+	case *SignatureMulti:
+		if wallet.Address(s.Multisig) != expected {
+			return fmt.Errorf("%w: %s, multisig is %s", ErrSenderMismatch, expected, wallet.Address(s.Multisig))
+		}
+		if len(s.Signatures) == 0 {
+			return fmt.Errorf("%w: multisig transaction has no signatures", ErrInvalidSignature)
+		}
+		for i, signature := range s.Signatures {
+			if err := signature.canonical(); err != nil {
+				return fmt.Errorf("signature #%d: %w", i, err)
+			}
+		}
+		return nil
+	default:
+		return ErrUnknownSignatureType
+	}
+}
+
+// Returns transaction decoded from its encoding, so it is checked like a raw transaction received from anyone.
+func decodeSigned(tx SignedTransaction) (*object, error) {
+	encoded, err := tx.Encode()
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := Decode(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return decoded.(*object), nil
+}
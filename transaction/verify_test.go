@@ -0,0 +1,109 @@
+package transaction
+
+import (
+	"errors"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"testing"
+)
+
+const signedSendSender = "Mx31e61a05adbd13c6b625262704bc305bf7725026"
+
+func TestVerify(t *testing.T) {
+	tx, err := Decode(signedSend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(tx, signedSendSender, TestNetChainID); err != nil {
+		t.Errorf("Verify got %v", err)
+	}
+	if err := Verify(tx, "Mx1b685a7c1e78726c48f619c497a07ed75fe00483", TestNetChainID); !errors.Is(err, ErrSenderMismatch) {
+		t.Errorf("Verify of other sender got %v, want ErrSenderMismatch", err)
+	}
+	if err := Verify(tx, signedSendSender, MainNetChainID); !errors.Is(err, ErrChainIDMismatch) {
+		t.Errorf("Verify of other chain id got %v, want ErrChainIDMismatch", err)
+	}
+
+	// the same signature with S = N - S and flipped V is recovered to the same sender, but is malleable
+	signature, err := tx.Signature()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := signature.(*Signature)
+	copied, err := Decode(signedSend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	malleable, err := copied.(*object).setSignature(&Signature{
+		V: big.NewInt(55 - s.V.Int64()),
+		R: s.R,
+		S: new(big.Int).Sub(crypto.S256().Params().N, s.S),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender, err := malleable.SenderAddress(); err != nil || sender != signedSendSender {
+		t.Fatalf("SenderAddress of malleable signature got %s, %v", sender, err)
+	}
+	if err := Verify(malleable, signedSendSender, TestNetChainID); !errors.Is(err, ErrMalleableSignature) {
+		t.Errorf("Verify of malleable signature got %v, want ErrMalleableSignature", err)
+	}
+
+	hash, err := SigningHash(malleable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	simple, err := malleable.SimpleSignatureData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifySignature(hash, simple, signedSendSender); !errors.Is(err, ErrMalleableSignature) {
+		t.Errorf("VerifySignature of malleable signature got %v, want ErrMalleableSignature", err)
+	}
+	if simple, err = tx.SimpleSignatureData(); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifySignature(hash, simple, signedSendSender); err != nil {
+		t.Errorf("VerifySignature got %v", err)
+	}
+	if err := VerifySignature(hash[1:], simple, signedSendSender); err == nil {
+		t.Error("VerifySignature of short hash want error")
+	}
+}
+
+func TestVerify_multisig(t *testing.T) {
+	keys, p := newPartiallySigned(t)
+	unsigned, err := p.unsigned()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(unsigned, multisigAddress, TestNetChainID); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify of unsigned transaction got %v, want ErrInvalidSignature", err)
+	}
+
+	tx, err := unsigned.Sign(multisigAddress, keys[0], keys[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(tx, multisigAddress, TestNetChainID); err != nil {
+		t.Errorf("Verify got %v", err)
+	}
+	if err := Verify(tx, signedSendSender, TestNetChainID); !errors.Is(err, ErrSenderMismatch) {
+		t.Errorf("Verify of other sender got %v, want ErrSenderMismatch", err)
+	}
+
+	signature, err := tx.Signature()
+	if err != nil {
+		t.Fatal(err)
+	}
+	multi := signature.(*SignatureMulti)
+	s := multi.Signatures[1]
+	multi.Signatures[1] = &Signature{V: big.NewInt(55 - s.V.Int64()), R: s.R, S: new(big.Int).Sub(crypto.S256().Params().N, s.S)}
+	malleable, err := tx.(*object).setSignature(multi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(malleable, multisigAddress, TestNetChainID); !errors.Is(err, ErrMalleableSignature) {
+		t.Errorf("Verify of malleable signature got %v, want ErrMalleableSignature", err)
+	}
+}
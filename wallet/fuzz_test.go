@@ -0,0 +1,38 @@
+// +build go1.18
+
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// Run "go test ./wallet -fuzz FuzzAddressToHex" to fuzz, seeds are run by "go test".
+func FuzzAddressToHex(f *testing.F) {
+	f.Add("Mx1b685a7c1e78726c48f619c497a07ed75fe00483")
+	f.Add("MX1B685A7C1E78726C48F619C497A07ED75FE00483")
+	f.Add("0x1b685a7c1e78726c48f619c497a07ed75fe00483")
+	f.Add("Mx")
+	f.Add("")
+	f.Fuzz(func(t *testing.T, address string) {
+		b, err := AddressToHex(address)
+		if IsValidAddress(address) != (err == nil) {
+			t.Fatalf("IsValidAddress(%q) does not match AddressToHex error %v", address, err)
+		}
+		if err != nil {
+			return
+		}
+		if len(b) != AddressLength {
+			t.Fatalf("AddressToHex(%q) got %d bytes", address, len(b))
+		}
+		if got := "Mx" + hex.EncodeToString(b); !strings.EqualFold(got, address) {
+			t.Fatalf("AddressToHex(%q) got %s", address, got)
+		}
+		parsed, err := ParseAddress(address)
+		if err != nil || !bytes.Equal(parsed[:], b) {
+			t.Fatalf("ParseAddress(%q) got %s, %v", address, parsed, err)
+		}
+	})
+}
@@ -1,30 +1,21 @@
 package wallet
 
 import (
-	"crypto/ecdsa"
-	"crypto/rand"
 	"encoding/hex"
 	"errors"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/tyler-smith/go-bip32"
-	"github.com/tyler-smith/go-bip39"
 	"strings"
 )
 
 // Generate mnemonic.
 func NewMnemonic() (string, error) {
-	entropy := make([]byte, 16)
-	_, err := rand.Read(entropy)
-	if err != nil {
-		return "", err
-	}
-	return bip39.NewMnemonic(entropy)
+	return NewMnemonicWithEntropy(Entropy128)
 }
 
 // Get seed from mnemonic.
 func Seed(mnemonic string) ([]byte, error) {
-	return bip39.NewSeedWithErrorChecking(mnemonic, "")
+	return SeedWithPassphrase(mnemonic, "")
 }
 
 // Get private key from seed.
@@ -70,15 +61,11 @@ func PrivateKeyBySeed(seed []byte) (string, error) {
 
 // Get Minter address from public key.
 func AddressByPublicKey(publicKey string) (string, error) {
-	publicKey = strings.Title(strings.ToLower(publicKey))
-	if !strings.HasPrefix(publicKey, "Mp") {
-		return "", errors.New("public key don't has prefix 'Mp'")
-	}
-	decodeString, err := hex.DecodeString(publicKey[2:])
+	key, err := ParsePublicKey(publicKey)
 	if err != nil {
 		return "", err
 	}
-	return addressToLowerPrefix0xToMx(common.BytesToAddress(crypto.Keccak256(decodeString)[12:]).String()), nil
+	return key.Address().String(), nil
 }
 
 func BytesToAddress(address [20]byte) string {
@@ -87,19 +74,27 @@ func BytesToAddress(address [20]byte) string {
 
 // Get public key from private key.
 func PublicKeyByPrivateKey(privateKey string) (string, error) {
-	key, err := crypto.HexToECDSA(privateKey)
+	key, err := ParsePrivateKey(privateKey)
 	if err != nil {
 		return "", err
 	}
-	return PubPrefix04ToMp(hex.EncodeToString(crypto.FromECDSAPub(key.Public().(*ecdsa.PublicKey)))), nil
+	defer key.Zero()
+	return key.PublicKey().String(), nil
 }
 
 func addressToLowerPrefix0xToMx(key string) string {
-	return strings.Replace(strings.ToLower(key), "0x", "Mx", 1)
+	return "Mx" + strings.TrimPrefix(strings.ToLower(key), "0x")
 }
 
+// Replace 0x04 prefix of uncompressed public key hex with "Mp".
 func PubPrefix04ToMp(key string) string {
-	return strings.Replace(key, "04", "Mp", 1)
+	if strings.HasPrefix(key, "Mp") {
+		return key
+	}
+	if len(key) == 2+2*PublicKeyLength && strings.HasPrefix(key, "04") {
+		return "Mp" + key[2:]
+	}
+	return "Mp" + key
 }
 
 func AddressToHex(address string) ([]byte, error) {
@@ -0,0 +1,160 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Prefix of signed messages. It makes signed message distinguishable from transaction and
+// prevents signing of arbitrary data like in Ethereum's personal_sign.
+const MessagePrefix = "\x19Minter Signed Message:\n"
+
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// Get hash of message to sign: keccak256(MessagePrefix + len(message) + message).
+func MessageHash(message []byte) []byte {
+	return crypto.Keccak256([]byte(MessagePrefix+strconv.Itoa(len(message))), message)
+}
+
+// Sign message with private key. Returns 0x-prefixed 65 bytes signature R || S || V, where V is 27 or 28.
+func SignMessage(privateKey string, message []byte) (string, error) {
+	return signHash(privateKey, MessageHash(message))
+}
+
+// Sign message with the wallet private key.
+func (w *Wallet) SignMessage(message []byte) (string, error) {
+	return SignMessage(w.PrivateKey(), message)
+}
+
+// Get Minter address of the message signer.
+func RecoverMessageAddress(message []byte, signature string) (string, error) {
+	return recoverHashAddress(MessageHash(message), signature)
+}
+
+// Check that message is signed by address.
+func VerifyMessage(address string, message []byte, signature string) (bool, error) {
+	signer, err := RecoverMessageAddress(message, signature)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(signer, address), nil
+}
+
+// Domain of typed data, it binds signature to the application.
+type TypedDataDomain struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	ChainID int    `json:"chain_id,omitempty"`
+}
+
+// Structured data to sign. Message is any value serializable to JSON.
+type TypedData struct {
+	Domain  TypedDataDomain
+	Type    string
+	Message interface{}
+}
+
+// Get hash of typed data:
+// keccak256("\x19\x01" || keccak256(domain) || keccak256(type) || keccak256(message)),
+// where domain and message are encoded as canonical JSON with sorted keys and without spaces.
+func (d *TypedData) Hash() ([]byte, error) {
+	domain, err := canonicalJSON(d.Domain)
+	if err != nil {
+		return nil, err
+	}
+	message, err := canonicalJSON(d.Message)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256([]byte{0x19, 0x01}, crypto.Keccak256(domain), crypto.Keccak256([]byte(d.Type)), crypto.Keccak256(message)), nil
+}
+
+// Sign typed data with private key.
+func SignTypedData(privateKey string, data *TypedData) (string, error) {
+	hash, err := data.Hash()
+	if err != nil {
+		return "", err
+	}
+	return signHash(privateKey, hash)
+}
+
+// Get Minter address of the typed data signer.
+func RecoverTypedDataAddress(data *TypedData, signature string) (string, error) {
+	hash, err := data.Hash()
+	if err != nil {
+		return "", err
+	}
+	return recoverHashAddress(hash, signature)
+}
+
+// Check that typed data is signed by address.
+func VerifyTypedData(address string, data *TypedData, signature string) (bool, error) {
+	signer, err := RecoverTypedDataAddress(data, signature)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(signer, address), nil
+}
+
+func canonicalJSON(v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var value interface{}
+	err = decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+func signHash(privateKey string, hash []byte) (string, error) {
+	key, err := ParsePrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+	defer key.Zero()
+	sig, err := crypto.Sign(hash, key.ECDSA())
+	if err != nil {
+		return "", err
+	}
+	sig[64] += 27
+	return "0x" + hex.EncodeToString(sig), nil
+}
+
+func recoverHashAddress(hash []byte, signature string) (string, error) {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil {
+		return "", err
+	}
+	if len(sig) != 65 {
+		return "", ErrInvalidSignature
+	}
+	sig = append([]byte{}, sig...)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+	if !crypto.ValidateSignatureValues(sig[64], r, s, true) {
+		return "", ErrInvalidSignature
+	}
+
+	publicKey, err := crypto.Ecrecover(hash, sig)
+	if err != nil {
+		return "", err
+	}
+	key, err := PublicKeyFromBytes(publicKey)
+	if err != nil {
+		return "", err
+	}
+	return key.Address().String(), nil
+}
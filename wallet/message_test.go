@@ -0,0 +1,86 @@
+package wallet
+
+import (
+	"testing"
+)
+
+func TestSignMessage(t *testing.T) {
+	message := []byte("login challenge 7f3a")
+	signature, err := SignMessage(validPrivateKey, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signature) != 132 {
+		t.Errorf("signature length got %d, want %d", len(signature), 132)
+	}
+
+	address, err := RecoverMessageAddress(message, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address != validAddress {
+		t.Errorf("address got %s, want %s", address, validAddress)
+	}
+
+	ok, err := VerifyMessage(validAddress, message, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("signature is not verified")
+	}
+
+	ok, err = VerifyMessage(validAddress, []byte("login challenge 7f3b"), signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Error("signature of another message is verified")
+	}
+
+	prefixed, err := SignMessage("0x"+validPrivateKey, message)
+	if err != nil || prefixed != signature {
+		t.Errorf("signature with 0x prefixed key got %s, %v, want %s", prefixed, err, signature)
+	}
+	if _, err := SignMessage("0x", message); err != ErrInvalidPrivateKey {
+		t.Errorf("error got %v, want %v", err, ErrInvalidPrivateKey)
+	}
+
+	if _, err := RecoverMessageAddress(message, signature[:130]); err != ErrInvalidSignature {
+		t.Errorf("error got %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestSignTypedData(t *testing.T) {
+	data := &TypedData{
+		Domain:  TypedDataDomain{Name: "example.com", Version: "1", ChainID: 1},
+		Type:    "Login",
+		Message: map[string]interface{}{"nonce": 42, "address": validAddress},
+	}
+	signature, err := SignTypedData(validPrivateKey, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type login struct {
+		Nonce   int    `json:"nonce"`
+		Address string `json:"address"`
+	}
+	sameData := &TypedData{Domain: data.Domain, Type: data.Type, Message: login{Nonce: 42, Address: validAddress}}
+	ok, err := VerifyTypedData(validAddress, sameData, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("signature of the same payload with different key order is not verified")
+	}
+
+	otherDomain := &TypedData{Domain: TypedDataDomain{Name: "evil.com", Version: "1", ChainID: 1}, Type: data.Type, Message: data.Message}
+	ok, err = VerifyTypedData(validAddress, otherDomain, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Error("signature is verified for another domain")
+	}
+}
@@ -0,0 +1,311 @@
+package wallet
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"github.com/tyler-smith/go-bip39/wordlists"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"sync"
+)
+
+// Size of the entropy used to generate mnemonic, in bits.
+type EntropySize int
+
+const (
+	Entropy128 EntropySize = 128 // 12 words
+	Entropy160 EntropySize = 160 // 15 words
+	Entropy192 EntropySize = 192 // 18 words
+	Entropy224 EntropySize = 224 // 21 words
+	Entropy256 EntropySize = 256 // 24 words
+)
+
+// Number of words in mnemonic generated from entropy of this size.
+func (s EntropySize) Words() int {
+	return (int(s) + int(s)/32) / 11
+}
+
+func (s EntropySize) valid() bool {
+	return s%32 == 0 && s >= Entropy128 && s <= Entropy256
+}
+
+// BIP39 wordlist language.
+type Language int
+
+const (
+	English Language = iota
+	ChineseSimplified
+	ChineseTraditional
+	French
+	Italian
+	Japanese
+	Korean
+	Spanish
+)
+
+var languages = []Language{English, ChineseSimplified, ChineseTraditional, French, Italian, Japanese, Korean, Spanish}
+
+func (l Language) String() string {
+	switch l {
+	case English:
+		return "english"
+	case ChineseSimplified:
+		return "chinese_simplified"
+	case ChineseTraditional:
+		return "chinese_traditional"
+	case French:
+		return "french"
+	case Italian:
+		return "italian"
+	case Japanese:
+		return "japanese"
+	case Korean:
+		return "korean"
+	case Spanish:
+		return "spanish"
+	default:
+		return fmt.Sprintf("Language(%d)", int(l))
+	}
+}
+
+func (l Language) list() []string {
+	switch l {
+	case English:
+		return wordlists.English
+	case ChineseSimplified:
+		return wordlists.ChineseSimplified
+	case ChineseTraditional:
+		return wordlists.ChineseTraditional
+	case French:
+		return wordlists.French
+	case Italian:
+		return wordlists.Italian
+	case Japanese:
+		return wordlists.Japanese
+	case Korean:
+		return wordlists.Korean
+	case Spanish:
+		return wordlists.Spanish
+	default:
+		return nil
+	}
+}
+
+// Japanese mnemonics are separated by ideographic space as required by BIP39.
+func (l Language) separator() string {
+	if l == Japanese {
+		return "　"
+	}
+	return " "
+}
+
+var (
+	wordIndexesMu sync.Mutex
+	wordIndexes   = make(map[Language]map[string]int)
+)
+
+// Reverse lookup of NFKD normalized words of the language wordlist.
+func (l Language) index() map[string]int {
+	wordIndexesMu.Lock()
+	defer wordIndexesMu.Unlock()
+
+	index, ok := wordIndexes[l]
+	if ok {
+		return index
+	}
+	list := l.list()
+	index = make(map[string]int, len(list))
+	for i, word := range list {
+		index[norm.NFKD.String(word)] = i
+	}
+	wordIndexes[l] = index
+	return index
+}
+
+var (
+	ErrEntropySize       = errors.New("entropy size must be a multiple of 32 bits in range [128, 256]")
+	ErrMnemonicWordCount = errors.New("mnemonic must contain 12, 15, 18, 21 or 24 words")
+	ErrMnemonicChecksum  = errors.New("mnemonic checksum is incorrect")
+	ErrUnknownLanguage   = errors.New("unknown mnemonic language")
+)
+
+// Error of mnemonic validation pointing to the word which is absent in the wordlist.
+type InvalidWordError struct {
+	Position int // starting from 1
+	Word     string
+	Language Language
+}
+
+func (e *InvalidWordError) Error() string {
+	return fmt.Sprintf("word #%d %q is not in the %s wordlist", e.Position, e.Word, e.Language)
+}
+
+// Generate mnemonic with the given entropy size and optional wordlist language (English by default).
+func NewMnemonicWithEntropy(size EntropySize, language ...Language) (string, error) {
+	if !size.valid() {
+		return "", ErrEntropySize
+	}
+	entropy := make([]byte, size/8)
+	_, err := rand.Read(entropy)
+	if err != nil {
+		return "", err
+	}
+	return MnemonicFromEntropy(entropy, language...)
+}
+
+// Get mnemonic encoding the entropy in the optional wordlist language (English by default).
+func MnemonicFromEntropy(entropy []byte, language ...Language) (string, error) {
+	size := EntropySize(len(entropy) * 8)
+	if !size.valid() {
+		return "", ErrEntropySize
+	}
+	lang := optionalLanguage(language)
+	list := lang.list()
+	if list == nil {
+		return "", ErrUnknownLanguage
+	}
+
+	checksum := sha256.Sum256(entropy)
+	bits := append(append([]byte{}, entropy...), checksum[0])
+	words := make([]string, size.Words())
+	for i := range words {
+		words[i] = list[readBits(bits, i*11, 11)]
+	}
+
+	return strings.Join(words, lang.separator()), nil
+}
+
+// Get entropy encoded by mnemonic. The wordlist language is detected automatically if not provided.
+func EntropyFromMnemonic(mnemonic string, language ...Language) ([]byte, error) {
+	words := mnemonicWords(mnemonic)
+	if !validWordCount(len(words)) {
+		return nil, ErrMnemonicWordCount
+	}
+
+	if len(language) != 0 {
+		return entropyFromWords(words, language[0])
+	}
+
+	var firstErr error
+	for _, lang := range languages {
+		entropy, err := entropyFromWords(words, lang)
+		if err == nil {
+			return entropy, nil
+		}
+		if firstErr == nil || betterMatch(err, firstErr) {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
+
+// Check that mnemonic has valid length, consists of words of a single wordlist and has correct checksum.
+// The *InvalidWordError is returned if mnemonic contains unknown word.
+func ValidateMnemonic(mnemonic string, language ...Language) error {
+	_, err := EntropyFromMnemonic(mnemonic, language...)
+	return err
+}
+
+// Get wordlist language of mnemonic.
+func MnemonicLanguage(mnemonic string) (Language, error) {
+	words := mnemonicWords(mnemonic)
+	if !validWordCount(len(words)) {
+		return 0, ErrMnemonicWordCount
+	}
+	for _, lang := range languages {
+		if _, err := entropyFromWords(words, lang); err == nil {
+			return lang, nil
+		}
+	}
+	return 0, ErrUnknownLanguage
+}
+
+// Get seed from mnemonic and BIP39 passphrase.
+func SeedWithPassphrase(mnemonic string, passphrase string) ([]byte, error) {
+	err := ValidateMnemonic(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	sentence := strings.Join(mnemonicWords(mnemonic), " ")
+	salt := "mnemonic" + norm.NFKD.String(passphrase)
+	return pbkdf2.Key([]byte(sentence), []byte(salt), 2048, 64, sha512.New), nil
+}
+
+func entropyFromWords(words []string, lang Language) ([]byte, error) {
+	index := lang.index()
+	if len(index) == 0 {
+		return nil, ErrUnknownLanguage
+	}
+
+	size := EntropySize(len(words) * 11 * 32 / 33)
+	bits := make([]byte, (len(words)*11+7)/8)
+	for i, word := range words {
+		idx, ok := index[word]
+		if !ok {
+			return nil, &InvalidWordError{Position: i + 1, Word: word, Language: lang}
+		}
+		writeBits(bits, i*11, 11, idx)
+	}
+
+	entropy := bits[:size/8]
+	checksumBits := int(size) / 32
+	checksum := sha256.Sum256(entropy)
+	if readBits(checksum[:], 0, checksumBits) != readBits(bits, int(size), checksumBits) {
+		return nil, ErrMnemonicChecksum
+	}
+
+	return append([]byte{}, entropy...), nil
+}
+
+// Prefer the error of the wordlist where the offending word appears later in mnemonic.
+func betterMatch(err, than error) bool {
+	if _, ok := err.(*InvalidWordError); !ok {
+		return true
+	}
+	thanWordErr, ok := than.(*InvalidWordError)
+	if !ok {
+		return false
+	}
+	return err.(*InvalidWordError).Position > thanWordErr.Position
+}
+
+func mnemonicWords(mnemonic string) []string {
+	return strings.Fields(norm.NFKD.String(mnemonic))
+}
+
+func validWordCount(n int) bool {
+	return n%3 == 0 && n >= 12 && n <= 24
+}
+
+// Returns the first of optional languages like EntropyFromMnemonic, English if none is given.
+func optionalLanguage(language []Language) Language {
+	if len(language) != 0 {
+		return language[0]
+	}
+	return English
+}
+
+func readBits(b []byte, offset, n int) int {
+	v := 0
+	for i := offset; i < offset+n; i++ {
+		v <<= 1
+		if b[i/8]&(0x80>>uint(i%8)) != 0 {
+			v |= 1
+		}
+	}
+	return v
+}
+
+func writeBits(b []byte, offset, n, v int) {
+	for i := 0; i < n; i++ {
+		if v&(1<<uint(n-1-i)) != 0 {
+			pos := offset + i
+			b[pos/8] |= 0x80 >> uint(pos%8)
+		}
+	}
+}
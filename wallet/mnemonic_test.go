@@ -0,0 +1,140 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestSeedWithPassphrase(t *testing.T) {
+	tests := []struct {
+		entropy  string
+		mnemonic string
+		seed     string
+	}{
+		{
+			entropy:  "00000000000000000000000000000000",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			seed:     "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			entropy:  "0000000000000000000000000000000000000000000000000000000000000000",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+			seed:     "bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+		},
+	}
+	for _, tt := range tests {
+		entropy, _ := hex.DecodeString(tt.entropy)
+		mnemonic, err := MnemonicFromEntropy(entropy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mnemonic != tt.mnemonic {
+			t.Errorf("mnemonic got %s, want %s", mnemonic, tt.mnemonic)
+		}
+
+		seed, err := SeedWithPassphrase(tt.mnemonic, "TREZOR")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(seed) != tt.seed {
+			t.Errorf("seed got %x, want %s", seed, tt.seed)
+		}
+	}
+}
+
+func TestNewMnemonicWithEntropy(t *testing.T) {
+	for _, size := range []EntropySize{Entropy128, Entropy160, Entropy192, Entropy224, Entropy256} {
+		for _, lang := range languages {
+			mnemonic, err := NewMnemonicWithEntropy(size, lang)
+			if err != nil {
+				t.Fatal(err)
+			}
+			words := mnemonicWords(mnemonic)
+			if len(words) != size.Words() {
+				t.Errorf("%s words count got %d, want %d", lang, len(words), size.Words())
+			}
+			if err := ValidateMnemonic(mnemonic, lang); err != nil {
+				t.Errorf("%s: %s", lang, err)
+			}
+			if _, err := SeedWithPassphrase(mnemonic, "passphrase"); err != nil {
+				t.Errorf("%s: %s", lang, err)
+			}
+		}
+	}
+
+	if _, err := NewMnemonicWithEntropy(100); err != ErrEntropySize {
+		t.Errorf("error got %v, want %v", err, ErrEntropySize)
+	}
+}
+
+func TestEntropyFromMnemonic(t *testing.T) {
+	entropy := bytes.Repeat([]byte{0x7f}, 32)
+	for _, lang := range languages {
+		mnemonic, err := MnemonicFromEntropy(entropy, lang)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := EntropyFromMnemonic(mnemonic)
+		if err != nil {
+			t.Fatalf("%s: %s", lang, err)
+		}
+		if !bytes.Equal(got, entropy) {
+			t.Errorf("%s entropy got %x, want %x", lang, got, entropy)
+		}
+	}
+}
+
+func TestMnemonicLanguage(t *testing.T) {
+	mnemonic, err := NewMnemonicWithEntropy(Entropy128, Spanish)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lang, err := MnemonicLanguage(mnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lang != Spanish {
+		t.Errorf("language got %s, want %s", lang, Spanish)
+	}
+
+	// the first of several languages is used for encoding and decoding
+	entropy := make([]byte, 16)
+	mnemonic, err = MnemonicFromEntropy(entropy, Spanish, English)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lang, err := MnemonicLanguage(mnemonic); err != nil || lang != Spanish {
+		t.Errorf("language of mnemonic with several languages got %s, %v, want %s", lang, err, Spanish)
+	}
+	if decoded, err := EntropyFromMnemonic(mnemonic, Spanish, English); err != nil || len(decoded) != len(entropy) {
+		t.Errorf("EntropyFromMnemonic with several languages got %x, %v", decoded, err)
+	}
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	err := ValidateMnemonic("suffer draft bacon typical start retire air sniff large biology mail diagram")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ValidateMnemonic("suffer draft bacon typical start retire air sniff large biology mail diagrm")
+	wordErr, ok := err.(*InvalidWordError)
+	if !ok {
+		t.Fatalf("error got %v, want *InvalidWordError", err)
+	}
+	if wordErr.Position != 12 || wordErr.Word != "diagrm" {
+		t.Errorf("invalid word got #%d %s, want #%d %s", wordErr.Position, wordErr.Word, 12, "diagrm")
+	}
+
+	err = ValidateMnemonic("suffer draft bacon typical start retire air sniff large biology mail mail")
+	if err != ErrMnemonicChecksum {
+		t.Errorf("error got %v, want %v", err, ErrMnemonicChecksum)
+	}
+
+	err = ValidateMnemonic(strings.Repeat("abandon ", 11))
+	if err != ErrMnemonicWordCount {
+		t.Errorf("error got %v, want %v", err, ErrMnemonicWordCount)
+	}
+}
@@ -0,0 +1,360 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// Shamir's secret sharing of the mnemonic entropy.
+//
+// The scheme follows the two-level construction of SLIP-0039: the entropy is split into group shares
+// with the group threshold, and every group share is split again into member shares with the member threshold.
+// Polynomials are evaluated over GF(256) with the Rijndael reduction polynomial x^8 + x^4 + x^3 + x + 1,
+// the secret is stored at x = 255 and the integrity digest HMAC-SHA256(random, secret)[:4] at x = 254.
+// Unlike SLIP-0039 the shares are not encoded as words and the secret is not encrypted with a passphrase:
+// a share is serialized as hex string with the trailing 4 bytes of SHA-256 checksum.
+
+const (
+	maxShareCount       = 16
+	shareDigestLength   = 4
+	shareChecksumLength = 4
+	shareHeaderLength   = 7
+	secretIndex         = 255
+	digestIndex         = 254
+)
+
+var (
+	ErrShareChecksum      = errors.New("share checksum is incorrect")
+	ErrShareDigest        = errors.New("share digest is incorrect, shares do not belong to the same secret")
+	ErrShareMismatch      = errors.New("shares belong to different split sets")
+	ErrShareDuplicate     = errors.New("shares have the same index but different values")
+	ErrInsufficientShares = errors.New("insufficient number of shares")
+	ErrInsufficientGroups = errors.New("insufficient number of groups")
+	ErrShareEncoding      = errors.New("invalid share encoding")
+)
+
+// Member threshold and count of a group.
+type GroupSpec struct {
+	Threshold int
+	Count     int
+}
+
+// Share of the secret.
+type Share struct {
+	Identifier      uint16
+	GroupIndex      byte
+	GroupThreshold  byte
+	GroupCount      byte
+	MemberIndex     byte
+	MemberThreshold byte
+	Value           []byte
+}
+
+// Encode share to hex string with checksum.
+func (s *Share) String() string {
+	b := make([]byte, 0, shareHeaderLength+len(s.Value)+shareChecksumLength)
+	b = append(b, byte(s.Identifier>>8), byte(s.Identifier), s.GroupIndex, s.GroupThreshold, s.GroupCount, s.MemberIndex, s.MemberThreshold)
+	b = append(b, s.Value...)
+	checksum := sha256.Sum256(b)
+	return hex.EncodeToString(append(b, checksum[:shareChecksumLength]...))
+}
+
+// Decode share from hex string and verify its checksum.
+func ParseShare(share string) (*Share, error) {
+	b, err := hex.DecodeString(share)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < shareHeaderLength+16+shareChecksumLength {
+		return nil, ErrShareEncoding
+	}
+	payload, checksum := b[:len(b)-shareChecksumLength], b[len(b)-shareChecksumLength:]
+	sum := sha256.Sum256(payload)
+	if !bytes.Equal(sum[:shareChecksumLength], checksum) {
+		return nil, ErrShareChecksum
+	}
+	s := &Share{
+		Identifier:      binary.BigEndian.Uint16(payload[:2]),
+		GroupIndex:      payload[2],
+		GroupThreshold:  payload[3],
+		GroupCount:      payload[4],
+		MemberIndex:     payload[5],
+		MemberThreshold: payload[6],
+		Value:           append([]byte{}, payload[shareHeaderLength:]...),
+	}
+	if s.GroupThreshold == 0 || s.GroupThreshold > s.GroupCount || s.GroupIndex >= s.GroupCount || s.MemberThreshold == 0 {
+		return nil, ErrShareEncoding
+	}
+	return s, nil
+}
+
+// Split mnemonic entropy into shares. Returns the member shares for each group.
+func SplitMnemonic(mnemonic string, groupThreshold int, groups []GroupSpec) ([][]*Share, error) {
+	entropy, err := EntropyFromMnemonic(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	return SplitEntropy(entropy, groupThreshold, groups)
+}
+
+// Split entropy into shares. Returns the member shares for each group.
+func SplitEntropy(entropy []byte, groupThreshold int, groups []GroupSpec) ([][]*Share, error) {
+	if len(entropy) < 16 || len(entropy)%2 != 0 {
+		return nil, errors.New("secret length must be even and at least 16 bytes")
+	}
+	if groupThreshold < 1 || groupThreshold > len(groups) || len(groups) > maxShareCount {
+		return nil, fmt.Errorf("group threshold must be in range [1, %d], groups count must not exceed %d", len(groups), maxShareCount)
+	}
+	for i, group := range groups {
+		if group.Threshold < 1 || group.Threshold > group.Count || group.Count > maxShareCount {
+			return nil, fmt.Errorf("group #%d: member threshold must be in range [1, count], count must not exceed %d", i+1, maxShareCount)
+		}
+		if group.Threshold == 1 && group.Count > 1 {
+			return nil, fmt.Errorf("group #%d: member threshold 1 requires a single member, use count 1", i+1)
+		}
+	}
+
+	var id [2]byte
+	_, err := rand.Read(id[:])
+	if err != nil {
+		return nil, err
+	}
+	identifier := binary.BigEndian.Uint16(id[:])
+
+	groupShares, err := splitSecret(groupThreshold, len(groups), entropy)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][]*Share, len(groups))
+	for i, group := range groups {
+		memberShares, err := splitSecret(group.Threshold, group.Count, groupShares[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = make([]*Share, 0, group.Count)
+		for j, value := range memberShares {
+			result[i] = append(result[i], &Share{
+				Identifier:      identifier,
+				GroupIndex:      byte(i),
+				GroupThreshold:  byte(groupThreshold),
+				GroupCount:      byte(len(groups)),
+				MemberIndex:     byte(j),
+				MemberThreshold: byte(group.Threshold),
+				Value:           value,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// Recover entropy from shares.
+func CombineShares(shares []*Share) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, ErrInsufficientShares
+	}
+	first := shares[0]
+
+	groups := make(map[byte]map[byte][]byte)
+	for _, share := range shares {
+		if share.Identifier != first.Identifier || share.GroupThreshold != first.GroupThreshold ||
+			share.GroupCount != first.GroupCount || len(share.Value) != len(first.Value) {
+			return nil, ErrShareMismatch
+		}
+		members, ok := groups[share.GroupIndex]
+		if !ok {
+			members = make(map[byte][]byte)
+			groups[share.GroupIndex] = members
+		}
+		if value, ok := members[share.MemberIndex]; ok && !bytes.Equal(value, share.Value) {
+			return nil, ErrShareDuplicate
+		}
+		members[share.MemberIndex] = share.Value
+	}
+
+	memberThresholds := make(map[byte]byte)
+	for _, share := range shares {
+		threshold, ok := memberThresholds[share.GroupIndex]
+		if ok && threshold != share.MemberThreshold {
+			return nil, ErrShareMismatch
+		}
+		memberThresholds[share.GroupIndex] = share.MemberThreshold
+	}
+
+	groupShares := make(map[byte][]byte)
+	for groupIndex, members := range groups {
+		if len(members) < int(memberThresholds[groupIndex]) {
+			continue
+		}
+		value, err := recoverSecret(int(memberThresholds[groupIndex]), members)
+		if err != nil {
+			return nil, err
+		}
+		groupShares[groupIndex] = value
+	}
+	if len(groupShares) < int(first.GroupThreshold) {
+		if len(groups) >= int(first.GroupThreshold) {
+			return nil, ErrInsufficientShares
+		}
+		return nil, ErrInsufficientGroups
+	}
+
+	return recoverSecret(int(first.GroupThreshold), groupShares)
+}
+
+// Recover mnemonic in the optional wordlist language (English by default) from shares.
+func RecoverMnemonic(shares []*Share, language ...Language) (string, error) {
+	entropy, err := CombineShares(shares)
+	if err != nil {
+		return "", err
+	}
+	return MnemonicFromEntropy(entropy, language...)
+}
+
+// Recover wallet from shares and BIP39 passphrase. Shares do not store the wordlist language,
+// the seed depends on the mnemonic words, so pass the language of the split mnemonic unless it is English.
+func RecoverWallet(shares []*Share, passphrase string, language ...Language) (*Wallet, error) {
+	mnemonic, err := RecoverMnemonic(shares, language...)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := SeedWithPassphrase(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	w, err := NewWallet(seed)
+	if err != nil {
+		return nil, err
+	}
+	w.Mnemonic = mnemonic
+	return w, nil
+}
+
+func splitSecret(threshold, count int, secret []byte) ([][]byte, error) {
+	if threshold == 1 {
+		shares := make([][]byte, count)
+		for i := range shares {
+			shares[i] = append([]byte{}, secret...)
+		}
+		return shares, nil
+	}
+
+	points := make(map[byte][]byte, threshold)
+	for i := 0; i < threshold-2; i++ {
+		value := make([]byte, len(secret))
+		_, err := rand.Read(value)
+		if err != nil {
+			return nil, err
+		}
+		points[byte(i)] = value
+	}
+
+	random := make([]byte, len(secret)-shareDigestLength)
+	_, err := rand.Read(random)
+	if err != nil {
+		return nil, err
+	}
+	points[digestIndex] = append(shareDigest(random, secret), random...)
+	points[secretIndex] = secret
+
+	shares := make([][]byte, count)
+	for i := range shares {
+		if value, ok := points[byte(i)]; ok {
+			shares[i] = value
+			continue
+		}
+		shares[i] = interpolate(points, byte(i))
+	}
+	return shares, nil
+}
+
+func recoverSecret(threshold int, shares map[byte][]byte) ([]byte, error) {
+	if threshold == 1 {
+		for _, value := range shares {
+			return append([]byte{}, value...), nil
+		}
+	}
+
+	secret := interpolate(shares, secretIndex)
+	digest := interpolate(shares, digestIndex)
+	if !hmac.Equal(digest[:shareDigestLength], shareDigest(digest[shareDigestLength:], secret)) {
+		return nil, ErrShareDigest
+	}
+	return secret, nil
+}
+
+func shareDigest(random, secret []byte) []byte {
+	mac := hmac.New(sha256.New, random)
+	mac.Write(secret)
+	return mac.Sum(nil)[:shareDigestLength]
+}
+
+// Lagrange interpolation of the polynomial defined by points, evaluated at x.
+func interpolate(points map[byte][]byte, x byte) []byte {
+	if value, ok := points[x]; ok {
+		return append([]byte{}, value...)
+	}
+
+	var length int
+	for _, value := range points {
+		length = len(value)
+		break
+	}
+
+	result := make([]byte, length)
+	for xi, yi := range points {
+		basis := byte(1)
+		for xj := range points {
+			if xj == xi {
+				continue
+			}
+			basis = gfMul(basis, gfDiv(x^xj, xi^xj))
+		}
+		for k := range result {
+			result[k] ^= gfMul(yi[k], basis)
+		}
+	}
+	return result
+}
+
+var gfExp, gfLog = gfTables()
+
+func gfTables() (exp [510]byte, log [256]byte) {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		exp[i] = x
+		exp[i+255] = x
+		log[x] = byte(i)
+		// multiply by the generator 3
+		x ^= gfXtime(x)
+	}
+	return exp, log
+}
+
+func gfXtime(x byte) byte {
+	if x&0x80 != 0 {
+		return x<<1 ^ 0x1b
+	}
+	return x << 1
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+255-int(gfLog[b])]
+}
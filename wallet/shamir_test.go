@@ -0,0 +1,142 @@
+package wallet
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitEntropy(t *testing.T) {
+	entropy := bytes.Repeat([]byte{0x42, 0x17}, 16)
+	groups, err := SplitEntropy(entropy, 2, []GroupSpec{{1, 1}, {2, 3}, {3, 5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		shares []*Share
+		err    error
+	}{
+		{"first and second groups", []*Share{groups[0][0], groups[1][0], groups[1][2]}, nil},
+		{"second and third groups", []*Share{groups[1][1], groups[1][2], groups[2][4], groups[2][0], groups[2][2]}, nil},
+		{"all shares", append(append(append([]*Share{}, groups[0]...), groups[1]...), groups[2]...), nil},
+		{"single group", []*Share{groups[2][0], groups[2][1], groups[2][2]}, ErrInsufficientGroups},
+		{"insufficient members", []*Share{groups[0][0], groups[1][0], groups[2][0]}, ErrInsufficientShares},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret, err := CombineShares(tt.shares)
+			if err != tt.err {
+				t.Fatalf("error got %v, want %v", err, tt.err)
+			}
+			if err == nil && !bytes.Equal(secret, entropy) {
+				t.Errorf("secret got %x, want %x", secret, entropy)
+			}
+		})
+	}
+}
+
+func TestSplitEntropy_invalidSpec(t *testing.T) {
+	entropy := make([]byte, 16)
+	for _, groups := range [][]GroupSpec{{{1, 2}}, {{3, 2}}, {{0, 0}}} {
+		if _, err := SplitEntropy(entropy, 1, groups); err == nil {
+			t.Errorf("%v: want error", groups)
+		}
+	}
+	if _, err := SplitEntropy(entropy, 2, []GroupSpec{{1, 1}}); err == nil {
+		t.Error("group threshold greater than groups count: want error")
+	}
+}
+
+func TestParseShare(t *testing.T) {
+	groups, err := SplitEntropy(make([]byte, 16), 1, []GroupSpec{{2, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded := groups[0][1].String()
+	share, err := ParseShare(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if share.String() != encoded {
+		t.Errorf("share got %s, want %s", share, encoded)
+	}
+
+	corrupted := []byte(encoded)
+	if corrupted[20] == '0' {
+		corrupted[20] = '1'
+	} else {
+		corrupted[20] = '0'
+	}
+	if _, err := ParseShare(string(corrupted)); err != ErrShareChecksum {
+		t.Errorf("error got %v, want %v", err, ErrShareChecksum)
+	}
+}
+
+func TestCombineShares_digest(t *testing.T) {
+	groups, err := SplitEntropy(make([]byte, 16), 1, []GroupSpec{{2, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := *groups[0][1]
+	tampered.Value = append([]byte{}, tampered.Value...)
+	tampered.Value[0] ^= 1
+
+	if _, err := CombineShares([]*Share{groups[0][0], &tampered}); err != ErrShareDigest {
+		t.Errorf("error got %v, want %v", err, ErrShareDigest)
+	}
+}
+
+func TestRecoverWallet(t *testing.T) {
+	mnemonic := "suffer draft bacon typical start retire air sniff large biology mail diagram"
+	groups, err := SplitMnemonic(mnemonic, 1, []GroupSpec{{2, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wallet, err := RecoverWallet([]*Share{groups[0][2], groups[0][0]}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wallet.Mnemonic != mnemonic {
+		t.Errorf("mnemonic got %s, want %s", wallet.Mnemonic, mnemonic)
+	}
+	if wallet.Address() != validAddress {
+		t.Errorf("address got %s, want %s", wallet.Address(), validAddress)
+	}
+}
+
+func TestRecoverWallet_language(t *testing.T) {
+	mnemonic, err := NewMnemonicWithEntropy(Entropy128, French)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed, err := SeedWithPassphrase(mnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := NewWallet(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups, err := SplitMnemonic(mnemonic, 1, []GroupSpec{{2, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wallet, err := RecoverWallet([]*Share{groups[0][1], groups[0][2]}, "", French)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wallet.Mnemonic != mnemonic || wallet.Address() != want.Address() {
+		t.Errorf("got %s of %q, want %s of %q", wallet.Address(), wallet.Mnemonic, want.Address(), mnemonic)
+	}
+	english, err := RecoverWallet([]*Share{groups[0][1], groups[0][2]}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if english.Address() == want.Address() {
+		t.Error("wallet recovered as English mnemonic has address of French one")
+	}
+}
@@ -0,0 +1,247 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"github.com/ethereum/go-ethereum/crypto"
+	"strings"
+)
+
+const (
+	PrivateKeyLength          = 32
+	PublicKeyLength           = 64
+	CompressedPublicKeyLength = 33
+	AddressLength             = 20
+)
+
+var (
+	ErrInvalidPrivateKey = errors.New("invalid private key")
+	ErrInvalidPublicKey  = errors.New("invalid public key")
+	ErrInvalidAddress    = errors.New("invalid address")
+)
+
+// Secp256k1 private key.
+type PrivateKey [PrivateKeyLength]byte
+
+// Parse private key from hex string with optional "0x" prefix.
+func ParsePrivateKey(key string) (*PrivateKey, error) {
+	b, err := decodeHex(key, "0x")
+	if err != nil || len(b) != PrivateKeyLength {
+		return nil, ErrInvalidPrivateKey
+	}
+	defer zero(b)
+	return PrivateKeyFromBytes(b)
+}
+
+// Get private key from 32 bytes.
+func PrivateKeyFromBytes(b []byte) (*PrivateKey, error) {
+	if len(b) != PrivateKeyLength {
+		return nil, ErrInvalidPrivateKey
+	}
+	// validates that key is in range [1, N)
+	if _, err := crypto.ToECDSA(b); err != nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	k := new(PrivateKey)
+	copy(k[:], b)
+	return k, nil
+}
+
+// Get private key from ecdsa.PrivateKey.
+func PrivateKeyFromECDSA(key *ecdsa.PrivateKey) (*PrivateKey, error) {
+	return PrivateKeyFromBytes(crypto.FromECDSA(key))
+}
+
+// Returns hex string of private key without prefix.
+func (k *PrivateKey) String() string {
+	return hex.EncodeToString(k[:])
+}
+
+// Returns ecdsa.PrivateKey.
+func (k *PrivateKey) ECDSA() *ecdsa.PrivateKey {
+	key, err := crypto.ToECDSA(k[:])
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// Returns public key of private key.
+func (k *PrivateKey) PublicKey() *PublicKey {
+	p, err := PublicKeyFromECDSA(&k.ECDSA().PublicKey)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Returns address of private key.
+func (k *PrivateKey) Address() Address {
+	return k.PublicKey().Address()
+}
+
+// Overwrite key bytes with zeros. The key must not be used after this call.
+func (k *PrivateKey) Zero() {
+	zero(k[:])
+}
+
+func (k PrivateKey) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *PrivateKey) UnmarshalText(text []byte) error {
+	key, err := ParsePrivateKey(string(text))
+	if err != nil {
+		return err
+	}
+	*k = *key
+	key.Zero()
+	return nil
+}
+
+// Uncompressed secp256k1 public key without 0x04 prefix.
+type PublicKey [PublicKeyLength]byte
+
+// Parse public key. Supported formats are "Mp" prefixed Minter public key and hex string with optional "0x" prefix
+// of uncompressed key with or without 0x04 prefix or of compressed key.
+func ParsePublicKey(key string) (*PublicKey, error) {
+	var (
+		b   []byte
+		err error
+	)
+	if len(key) > 2 && strings.EqualFold(key[:2], "Mp") {
+		b, err = hex.DecodeString(key[2:])
+	} else {
+		b, err = decodeHex(key, "0x")
+	}
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	return PublicKeyFromBytes(b)
+}
+
+// Get public key from bytes of uncompressed key with or without 0x04 prefix or of compressed key.
+func PublicKeyFromBytes(b []byte) (*PublicKey, error) {
+	switch len(b) {
+	case PublicKeyLength:
+		b = append([]byte{0x04}, b...)
+	case PublicKeyLength + 1:
+	case CompressedPublicKeyLength:
+		key, err := crypto.DecompressPubkey(b)
+		if err != nil {
+			return nil, ErrInvalidPublicKey
+		}
+		return PublicKeyFromECDSA(key)
+	default:
+		return nil, ErrInvalidPublicKey
+	}
+	key, err := crypto.UnmarshalPubkey(b)
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	return PublicKeyFromECDSA(key)
+}
+
+// Get public key from ecdsa.PublicKey.
+func PublicKeyFromECDSA(key *ecdsa.PublicKey) (*PublicKey, error) {
+	b := crypto.FromECDSAPub(key)
+	if len(b) != PublicKeyLength+1 {
+		return nil, ErrInvalidPublicKey
+	}
+	p := new(PublicKey)
+	copy(p[:], b[1:])
+	return p, nil
+}
+
+// Returns Minter public key "Mp...".
+func (p *PublicKey) String() string {
+	return "Mp" + hex.EncodeToString(p[:])
+}
+
+// Returns 65 bytes of uncompressed key with 0x04 prefix.
+func (p *PublicKey) Bytes() []byte {
+	return append([]byte{0x04}, p[:]...)
+}
+
+// Returns 33 bytes of compressed key.
+func (p *PublicKey) Compressed() []byte {
+	return crypto.CompressPubkey(p.ECDSA())
+}
+
+// Returns ecdsa.PublicKey.
+func (p *PublicKey) ECDSA() *ecdsa.PublicKey {
+	key, err := crypto.UnmarshalPubkey(p.Bytes())
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// Returns Minter address of public key.
+func (p *PublicKey) Address() Address {
+	var a Address
+	copy(a[:], crypto.Keccak256(p[:])[12:])
+	return a
+}
+
+func (p PublicKey) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *PublicKey) UnmarshalText(text []byte) error {
+	key, err := ParsePublicKey(string(text))
+	if err != nil {
+		return err
+	}
+	*p = *key
+	return nil
+}
+
+// Minter address.
+type Address [AddressLength]byte
+
+// Parse "Mx" prefixed Minter address.
+func ParseAddress(address string) (Address, error) {
+	var a Address
+	if len(address) != 2+2*AddressLength || !strings.EqualFold(address[:2], "Mx") {
+		return a, ErrInvalidAddress
+	}
+	b, err := hex.DecodeString(address[2:])
+	if err != nil {
+		return a, ErrInvalidAddress
+	}
+	copy(a[:], b)
+	return a, nil
+}
+
+// Returns Minter address "Mx..." in lower case.
+func (a Address) String() string {
+	return "Mx" + hex.EncodeToString(a[:])
+}
+
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Address) UnmarshalText(text []byte) error {
+	address, err := ParseAddress(string(text))
+	if err != nil {
+		return err
+	}
+	*a = address
+	return nil
+}
+
+func decodeHex(s, prefix string) ([]byte, error) {
+	if len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+		s = s[len(prefix):]
+	}
+	return hex.DecodeString(s)
+}
+
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
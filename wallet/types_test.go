@@ -0,0 +1,124 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParsePrivateKey(t *testing.T) {
+	for _, s := range []string{validPrivateKey, "0x" + validPrivateKey, strings.ToUpper(validPrivateKey)} {
+		key, err := ParsePrivateKey(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key.String() != validPrivateKey {
+			t.Errorf("PrivateKey got %s, want %s", key, validPrivateKey)
+		}
+		if key.PublicKey().String() != validPublicKey {
+			t.Errorf("PublicKey got %s, want %s", key.PublicKey(), validPublicKey)
+		}
+		if key.Address().String() != validAddress {
+			t.Errorf("Address got %s, want %s", key.Address(), validAddress)
+		}
+	}
+
+	for _, s := range []string{"", validPrivateKey[2:], strings.Repeat("0", 64), strings.Repeat("f", 64), "zz" + validPrivateKey[2:]} {
+		if _, err := ParsePrivateKey(s); err != ErrInvalidPrivateKey {
+			t.Errorf("%q: error got %v, want %v", s, err, ErrInvalidPrivateKey)
+		}
+	}
+}
+
+func TestPrivateKey_Zero(t *testing.T) {
+	key, err := ParsePrivateKey(validPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key.Zero()
+	if *key != (PrivateKey{}) {
+		t.Errorf("PrivateKey got %s, want zeros", key)
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key, err := ParsePublicKey(validPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compressed := hex.EncodeToString(key.Compressed())
+	for _, s := range []string{
+		"mp" + validPublicKey[2:],
+		"04" + validPublicKey[2:],
+		"0x04" + validPublicKey[2:],
+		validPublicKey[2:],
+		compressed,
+		"0x" + compressed,
+	} {
+		parsed, err := ParsePublicKey(s)
+		if err != nil {
+			t.Fatalf("%s: %s", s, err)
+		}
+		if parsed.String() != validPublicKey {
+			t.Errorf("PublicKey got %s, want %s", parsed, validPublicKey)
+		}
+	}
+
+	invalid := "Mp" + strings.Repeat("0", 128)
+	if _, err := ParsePublicKey(invalid); err != ErrInvalidPublicKey {
+		t.Errorf("error got %v, want %v", err, ErrInvalidPublicKey)
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	address, err := ParseAddress(strings.ToUpper(validAddress[:10]) + validAddress[10:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address.String() != validAddress {
+		t.Errorf("Address got %s, want %s", address, validAddress)
+	}
+
+	for _, s := range []string{"", validAddress[:41], "Mp" + validAddress[2:], "Mx" + strings.Repeat("z", 40)} {
+		if _, err := ParseAddress(s); err != ErrInvalidAddress {
+			t.Errorf("%q: error got %v, want %v", s, err, ErrInvalidAddress)
+		}
+	}
+}
+
+func TestTypes_JSON(t *testing.T) {
+	type keys struct {
+		PrivateKey PrivateKey
+		PublicKey  *PublicKey
+		Address    Address
+	}
+	src := `{"PrivateKey":"` + validPrivateKey + `","PublicKey":"` + validPublicKey + `","Address":"` + validAddress + `"}`
+
+	var k keys
+	if err := json.Unmarshal([]byte(src), &k); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != src {
+		t.Errorf("JSON got %s, want %s", b, src)
+	}
+
+	if err := json.Unmarshal([]byte(`{"Address":"Mx00"}`), &k); err != ErrInvalidAddress {
+		t.Errorf("error got %v, want %v", err, ErrInvalidAddress)
+	}
+}
+
+func TestPubPrefix04ToMp(t *testing.T) {
+	key := "b8da2fba2600422ab99c2b64585b85a17d9f47d2f111250b7c4c660d936df7d08c2771c2a7bc22099764e58c5126dada3bba1438ab640d102f96b08ca64369e8"
+	if got := PubPrefix04ToMp(key); got != "Mp"+key {
+		t.Errorf("PubPrefix04ToMp got %s, want %s", got, "Mp"+key)
+	}
+	if got := PubPrefix04ToMp("04" + key); got != "Mp"+key {
+		t.Errorf("PubPrefix04ToMp got %s, want %s", got, "Mp"+key)
+	}
+}
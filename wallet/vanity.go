@@ -0,0 +1,208 @@
+package wallet
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Options of vanity address search.
+type VanityOptions struct {
+	// Hex prefix of address, "Mx" may be omitted.
+	Prefix string
+	// Hex suffix of address.
+	Suffix string
+	// Number of goroutines, runtime.NumCPU() by default.
+	Workers int
+	// Generate wallet from mnemonic. It is much slower than generation of raw private keys,
+	// but the result can be restored from mnemonic.
+	Mnemonic bool
+	// Called periodically with search progress.
+	Progress func(VanityProgress)
+	// Interval of progress reporting, a second by default.
+	ProgressInterval time.Duration
+}
+
+// Progress of vanity address search.
+type VanityProgress struct {
+	Attempts uint64
+	Elapsed  time.Duration
+	// Keys per second.
+	Rate float64
+	// Expected number of attempts.
+	Difficulty float64
+	// Probability to have found the address by this number of attempts.
+	Probability float64
+}
+
+// Expected number of attempts to find address with given prefix and suffix.
+func VanityDifficulty(prefix, suffix string) float64 {
+	prefix, suffix = vanityPattern(prefix, suffix)
+	return math.Pow(16, float64(len(prefix)+len(suffix)))
+}
+
+// Generate wallet with address matching prefix and suffix of options.
+// The search is stopped with an error when the context is done.
+func GenerateVanity(ctx context.Context, options VanityOptions) (*Wallet, error) {
+	prefix, suffix := vanityPattern(options.Prefix, options.Suffix)
+	if _, err := hex.DecodeString(evenHex(prefix + suffix)); err != nil {
+		return nil, errors.New("vanity pattern must contain hex characters only")
+	}
+	if len(prefix)+len(suffix) > 40 {
+		return nil, errors.New("vanity pattern is longer than address")
+	}
+
+	workers := options.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	interval := options.ProgressInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		attempts uint64
+		once     sync.Once
+		result   *Wallet
+		resErr   error
+		wg       sync.WaitGroup
+	)
+	found := func(w *Wallet, err error) {
+		once.Do(func() {
+			result, resErr = w, err
+			cancel()
+		})
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				atomic.AddUint64(&attempts, 1)
+				w, ok, err := vanityAttempt(prefix, suffix, options.Mnemonic)
+				if err != nil {
+					found(nil, err)
+					return
+				}
+				if ok {
+					found(w, nil)
+					return
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	var reporter sync.WaitGroup
+	if options.Progress != nil {
+		difficulty := VanityDifficulty(prefix, suffix)
+		start := time.Now()
+		reporter.Add(1)
+		go func() {
+			defer reporter.Done()
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					n := atomic.LoadUint64(&attempts)
+					elapsed := time.Since(start)
+					options.Progress(VanityProgress{
+						Attempts:    n,
+						Elapsed:     elapsed,
+						Rate:        float64(n) / elapsed.Seconds(),
+						Difficulty:  difficulty,
+						Probability: 1 - math.Pow(1-1/difficulty, float64(n)),
+					})
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(done)
+	// Progress is not called after return
+	reporter.Wait()
+
+	if result == nil && resErr == nil {
+		return nil, ctx.Err()
+	}
+	return result, resErr
+}
+
+func vanityAttempt(prefix, suffix string, withMnemonic bool) (*Wallet, bool, error) {
+	if withMnemonic {
+		mnemonic, err := NewMnemonic()
+		if err != nil {
+			return nil, false, err
+		}
+		seed, err := Seed(mnemonic)
+		if err != nil {
+			return nil, false, err
+		}
+		w, err := NewWallet(seed)
+		if err != nil {
+			return nil, false, err
+		}
+		if !vanityMatch(w.Address()[2:], prefix, suffix) {
+			return nil, false, nil
+		}
+		w.Mnemonic = mnemonic
+		return w, true, nil
+	}
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, false, err
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	if !vanityMatch(hex.EncodeToString(address[:]), prefix, suffix) {
+		return nil, false, nil
+	}
+
+	prKey := hex.EncodeToString(crypto.FromECDSA(key))
+	pubKey, err := PublicKeyByPrivateKey(prKey)
+	if err != nil {
+		return nil, false, err
+	}
+	return &Wallet{
+		Data: &Data{
+			PrivateKey: prKey,
+			PublicKey:  pubKey,
+			Address:    BytesToAddress(address),
+		},
+	}, true, nil
+}
+
+func vanityMatch(address, prefix, suffix string) bool {
+	return strings.HasPrefix(address, prefix) && strings.HasSuffix(address, suffix)
+}
+
+func vanityPattern(prefix, suffix string) (string, string) {
+	prefix = strings.ToLower(prefix)
+	if strings.HasPrefix(prefix, "mx") {
+		prefix = prefix[2:]
+	}
+	return prefix, strings.ToLower(suffix)
+}
+
+func evenHex(s string) string {
+	if len(s)%2 == 1 {
+		return s + "0"
+	}
+	return s
+}
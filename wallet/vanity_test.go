@@ -0,0 +1,73 @@
+package wallet
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateVanity(t *testing.T) {
+	var progress []VanityProgress
+	w, err := GenerateVanity(context.Background(), VanityOptions{
+		Prefix:           "Mx0",
+		Suffix:           "a",
+		Workers:          2,
+		Progress:         func(p VanityProgress) { progress = append(progress, p) },
+		ProgressInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(w.Address(), "Mx0") || !strings.HasSuffix(w.Address(), "a") {
+		t.Errorf("address %s does not match pattern", w.Address())
+	}
+
+	pubKey, err := PublicKeyByPrivateKey(w.PrivateKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, err := AddressByPublicKey(pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address != w.Address() {
+		t.Errorf("address got %s, want %s", w.Address(), address)
+	}
+}
+
+func TestGenerateVanity_mnemonic(t *testing.T) {
+	w, err := GenerateVanity(context.Background(), VanityOptions{Prefix: "f", Mnemonic: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed, err := Seed(w.Mnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := NewWallet(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Address() != w.Address() || !strings.HasPrefix(w.Address(), "Mxf") {
+		t.Errorf("address got %s, want %s with prefix Mxf", restored.Address(), w.Address())
+	}
+}
+
+func TestGenerateVanity_cancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := GenerateVanity(ctx, VanityOptions{Prefix: strings.Repeat("0", 40)})
+	if err != context.DeadlineExceeded {
+		t.Errorf("error got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestVanityDifficulty(t *testing.T) {
+	if d := VanityDifficulty("Mxab", "c"); d != 4096 {
+		t.Errorf("difficulty got %f, want %d", d, 4096)
+	}
+	if _, err := GenerateVanity(context.Background(), VanityOptions{Prefix: "xyz"}); err == nil {
+		t.Error("want error for non-hex pattern")
+	}
+}
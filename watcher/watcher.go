@@ -0,0 +1,364 @@
+// Package watcher follows blocks and reports incoming transfers to a set of watched addresses,
+// e.g. to credit deposits of exchange users.
+package watcher
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/nikolaev-dev/sdk/api"
+	"github.com/nikolaev-dev/sdk/follower"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Types of transactions transferring coins to the recipient.
+const (
+	TypeSend        = 1
+	TypeRedeemCheck = 9
+	TypeMultisend   = 13
+)
+
+// Incoming transfer to a watched address.
+type Deposit struct {
+	Hash   string
+	Height int
+	// Type of transaction: TypeSend, TypeMultisend or TypeRedeemCheck.
+	Type int
+	// Position of the transfer in the multisend list, 0 for other types.
+	Index int
+	// Sender of transaction, issuer of check for TypeRedeemCheck.
+	From   string
+	To     string
+	Coin   string
+	Amount *big.Int
+}
+
+// DepositHandler is called sequentially in order of heights, error stops the watcher.
+type DepositHandler func(ctx context.Context, deposit *Deposit) error
+
+// Option configures the watcher created by New.
+type Option func(*Watcher)
+
+// Set number of confirmations: deposits of block at height H are reported when the node head is H+n-1, 1 by default.
+func WithConfirmations(n int) Option {
+	return func(w *Watcher) {
+		w.confirmations = n
+	}
+}
+
+// Set options of the underlying block follower: start height, concurrency, cursor store, etc.
+func WithFollowerOptions(opts ...follower.Option) Option {
+	return func(w *Watcher) {
+		w.followerOptions = append(w.followerOptions, opts...)
+	}
+}
+
+// Set handler of errors, which do not stop the watcher, e.g. ErrInvalidCheck of skipped RedeemCheck transactions.
+func WithErrorHandler(handler func(error)) Option {
+	return func(w *Watcher) {
+		w.onError = handler
+	}
+}
+
+// Add deposit handler.
+func OnDeposit(handler DepositHandler) Option {
+	return func(w *Watcher) {
+		w.onDeposit = append(w.onDeposit, handler)
+	}
+}
+
+// Watcher of deposits to the set of addresses.
+type Watcher struct {
+	node            api.NodeClient
+	confirmations   int
+	followerOptions []follower.Option
+	onDeposit       []DepositHandler
+	onError         func(error)
+
+	mu        sync.RWMutex
+	addresses map[string]bool
+
+	// hashes of handled transactions by height, to skip transactions repeated by the node
+	seen       map[string]int
+	seenHeight int
+}
+
+// Number of heights for which handled transaction hashes are remembered.
+const seenHeights = 1000
+
+// Create watcher of deposits to addresses.
+func New(node api.NodeClient, addresses []string, opts ...Option) *Watcher {
+	w := &Watcher{
+		node:          node,
+		confirmations: 1,
+		addresses:     make(map[string]bool, len(addresses)),
+		seen:          make(map[string]int),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.confirmations < 1 {
+		w.confirmations = 1
+	}
+	w.Watch(addresses...)
+	return w
+}
+
+// Add addresses to the watched set, it is safe to call while the watcher is running.
+func (w *Watcher) Watch(addresses ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, address := range addresses {
+		w.addresses[strings.ToLower(address)] = true
+	}
+}
+
+// Remove addresses from the watched set.
+func (w *Watcher) Unwatch(addresses ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, address := range addresses {
+		delete(w.addresses, strings.ToLower(address))
+	}
+}
+
+// Check that address is watched.
+func (w *Watcher) Watched(address string) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.addresses[strings.ToLower(address)]
+}
+
+// Follow blocks with the required number of confirmations and report deposits until the context is done or a handler returns error.
+func (w *Watcher) Run(ctx context.Context) error {
+	node := w.node
+	if w.confirmations > 1 {
+		node = &confirmedNode{NodeClient: node, lag: w.confirmations - 1}
+	}
+	opts := append(append([]follower.Option{}, w.followerOptions...), follower.OnBlock(w.handleBlock))
+	return follower.New(node, opts...).Run(ctx)
+}
+
+func (w *Watcher) handleBlock(ctx context.Context, block *api.BlockResult) error {
+	height, err := strconv.Atoi(block.Height)
+	if err != nil {
+		return err
+	}
+	w.forget(height)
+
+	for i := range block.Transactions {
+		transaction := &block.Transactions[i]
+		if transaction.Code != 0 {
+			continue
+		}
+		hash := strings.ToLower(transaction.Hash)
+		if _, ok := w.seen[hash]; ok {
+			continue
+		}
+
+		deposits, err := w.Deposits(transaction)
+		if errors.Is(err, ErrInvalidCheck) {
+			// check of unknown format must not stop reporting deposits of other transactions
+			w.reportError(fmt.Errorf("transaction %s is skipped: %w", transaction.Hash, err))
+			w.seen[hash] = height
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("transaction %s: %w", transaction.Hash, err)
+		}
+		for _, deposit := range deposits {
+			deposit.Height = height
+			for _, handler := range w.onDeposit {
+				if err := handler(ctx, deposit); err != nil {
+					return err
+				}
+			}
+		}
+		w.seen[hash] = height
+	}
+	return nil
+}
+
+func (w *Watcher) reportError(err error) {
+	if w.onError != nil {
+		w.onError(err)
+	}
+}
+
+func (w *Watcher) forget(height int) {
+	if height-w.seenHeight < seenHeights {
+		return
+	}
+	for hash, h := range w.seen {
+		if height-h >= seenHeights {
+			delete(w.seen, hash)
+		}
+	}
+	w.seenHeight = height
+}
+
+// Get transfers of transaction to the watched addresses.
+func (w *Watcher) Deposits(transaction *api.TransactionResult) ([]*Deposit, error) {
+	transfers, err := Transfers(transaction)
+	if err != nil {
+		return nil, err
+	}
+	deposits := transfers[:0]
+	for _, transfer := range transfers {
+		if w.Watched(transfer.To) {
+			deposits = append(deposits, transfer)
+		}
+	}
+	return deposits, nil
+}
+
+type transfer struct {
+	Coin  string `json:"coin"`
+	To    string `json:"to"`
+	Value string `json:"value"`
+}
+
+// Get all transfers of Send, Multisend and RedeemCheck transaction, other types have no transfers.
+func Transfers(transaction *api.TransactionResult) ([]*Deposit, error) {
+	height, _ := strconv.Atoi(transaction.Height)
+	deposit := func(index int, from, to, coin string, amount *big.Int) *Deposit {
+		return &Deposit{
+			Hash:   transaction.Hash,
+			Height: height,
+			Type:   transaction.Type,
+			Index:  index,
+			From:   from,
+			To:     to,
+			Coin:   coin,
+			Amount: amount,
+		}
+	}
+
+	switch transaction.Type {
+	case TypeSend:
+		var data transfer
+		if err := decodeData(transaction, &data); err != nil {
+			return nil, err
+		}
+		amount, err := parseAmount(data.Value)
+		if err != nil {
+			return nil, err
+		}
+		return []*Deposit{deposit(0, transaction.From, data.To, data.Coin, amount)}, nil
+	case TypeMultisend:
+		var data struct {
+			List []transfer `json:"list"`
+		}
+		if err := decodeData(transaction, &data); err != nil {
+			return nil, err
+		}
+		deposits := make([]*Deposit, 0, len(data.List))
+		for i, item := range data.List {
+			amount, err := parseAmount(item.Value)
+			if err != nil {
+				return nil, err
+			}
+			deposits = append(deposits, deposit(i, transaction.From, item.To, item.Coin, amount))
+		}
+		return deposits, nil
+	case TypeRedeemCheck:
+		var data struct {
+			RawCheck string `json:"raw_check"`
+		}
+		if err := decodeData(transaction, &data); err != nil {
+			return nil, err
+		}
+		check, err := decodeCheck(data.RawCheck)
+		if err != nil {
+			return nil, err
+		}
+		issuer := transaction.Tags.TxFrom
+		if issuer != "" && !strings.HasPrefix(issuer, "Mx") {
+			issuer = "Mx" + issuer
+		}
+		return []*Deposit{deposit(0, issuer, transaction.From, check.coin(), check.Value)}, nil
+	}
+	return nil, nil
+}
+
+func decodeData(transaction *api.TransactionResult, v interface{}) error {
+	data, err := json.Marshal(transaction.Data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func parseAmount(value string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
+
+// Check in the node format, GasCoin pays the commission of its redeeming.
+type check struct {
+	Nonce    []byte
+	ChainID  uint
+	DueBlock uint64
+	Coin     [10]byte
+	Value    *big.Int
+	GasCoin  [10]byte
+	Lock     *big.Int
+	V        *big.Int
+	R        *big.Int
+	S        *big.Int
+}
+
+func (c *check) coin() string {
+	return strings.TrimRight(string(c.Coin[:]), "\x00")
+}
+
+// Error of RedeemCheck transaction with check, which cannot be decoded.
+var ErrInvalidCheck = errors.New("invalid check")
+
+func decodeCheck(rawCheck string) (*check, error) {
+	if !strings.HasPrefix(rawCheck, "Mc") {
+		return nil, ErrInvalidCheck
+	}
+	data, err := hex.DecodeString(rawCheck[2:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCheck, err)
+	}
+	c := new(check)
+	err = rlp.DecodeBytes(data, c)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCheck, err)
+	}
+	if c.Value == nil {
+		return nil, ErrInvalidCheck
+	}
+	return c, nil
+}
+
+// Node client reporting the head lowered by lag, so blocks are followed only after lag more blocks are committed.
+type confirmedNode struct {
+	api.NodeClient
+	lag int
+}
+
+func (n *confirmedNode) Status() (*api.StatusResult, error) {
+	status, err := n.NodeClient.Status()
+	if err != nil {
+		return nil, err
+	}
+	head, err := strconv.Atoi(status.LatestBlockHeight)
+	if err != nil {
+		return nil, err
+	}
+	confirmed := *status
+	confirmed.LatestBlockHeight = strconv.Itoa(head - n.lag)
+	return &confirmed, nil
+}
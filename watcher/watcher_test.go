@@ -0,0 +1,230 @@
+package watcher
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/nikolaev-dev/sdk/api"
+	"github.com/nikolaev-dev/sdk/follower"
+	"math/big"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	alice = "Mxeeda61bbe9929bf883af6b22f5796e4b92563ba4"
+	bob   = "Mx1b685a7c1e78726c48f619c497a07ed75fe00483"
+	carol = "Mxfe60014a6e9ac91618f5d1cab3fd58cded61ee99"
+)
+
+type fakeNode struct {
+	api.NodeClient
+
+	mu     sync.Mutex
+	blocks []*api.BlockResult
+}
+
+func (n *fakeNode) add(transactions ...api.TransactionResult) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	height := strconv.Itoa(len(n.blocks) + 1)
+	for i := range transactions {
+		transactions[i].Height = height
+	}
+	n.blocks = append(n.blocks, &api.BlockResult{Height: height, Hash: "Mh" + height, Transactions: transactions})
+}
+
+func (n *fakeNode) Status() (*api.StatusResult, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return &api.StatusResult{LatestBlockHeight: strconv.Itoa(len(n.blocks))}, nil
+}
+
+func (n *fakeNode) Block(height int) (*api.BlockResult, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.blocks[height-1], nil
+}
+
+func send(hash, from, to, coin, value string) api.TransactionResult {
+	return api.TransactionResult{
+		Hash: hash,
+		From: from,
+		Type: TypeSend,
+		Data: map[string]interface{}{"coin": coin, "to": to, "value": value},
+	}
+}
+
+// Returns check issued by the key in the node format, it is encoded as a list of fields independently of check type.
+func issueCheck(t *testing.T, coin string, value *big.Int) string {
+	key, err := crypto.HexToECDSA("64e27afaab363f21eec05291084367f6f1297a7b280d69d672febecda94a09ea")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var symbol, gasCoin [10]byte
+	copy(symbol[:], coin)
+	copy(gasCoin[:], "MNT")
+	passphrase, err := crypto.ToECDSA(crypto.Keccak256([]byte("pass")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock, err := crypto.Sign(crypto.Keccak256([]byte(alice)), passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := []interface{}{[]byte("1"), uint(2), uint64(999999), symbol, value, gasCoin, new(big.Int).SetBytes(lock)}
+	unsigned, err := rlp.EncodeToBytes(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := crypto.Sign(crypto.Keccak256(unsigned), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields = append(fields, big.NewInt(int64(signature[64])+27), new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:64]))
+	data, err := rlp.EncodeToBytes(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Mc" + hex.EncodeToString(data)
+}
+
+func TestWatcher_Run(t *testing.T) {
+	node := &fakeNode{}
+	multisend := api.TransactionResult{
+		Hash: "Mt02",
+		From: carol,
+		Type: TypeMultisend,
+		Data: map[string]interface{}{"list": []interface{}{
+			map[string]interface{}{"coin": "MNT", "to": bob, "value": "5"},
+			map[string]interface{}{"coin": "BIP", "to": alice, "value": "100000000000000000000000000"},
+		}},
+	}
+	redeem := api.TransactionResult{
+		Hash: "Mt03",
+		From: alice,
+		Type: TypeRedeemCheck,
+		Data: map[string]interface{}{"raw_check": issueCheck(t, "CHECK", big.NewInt(42)), "proof": "00"},
+	}
+	redeem.Tags.TxFrom = "fe60014a6e9ac91618f5d1cab3fd58cded61ee99"
+	failed := send("Mt04", bob, alice, "MNT", "7")
+	failed.Code = 107
+
+	node.add(send("Mt01", bob, alice, "MNT", "1000000000000000000"), send("Mt05", alice, bob, "MNT", "1"))
+	node.add(multisend, redeem, failed)
+	node.add(send("Mt01", bob, alice, "MNT", "1000000000000000000"))
+
+	deposits := make(chan *Deposit, 10)
+	w := New(node, []string{alice},
+		WithConfirmations(3),
+		WithFollowerOptions(follower.WithPollInterval(time.Millisecond)),
+		OnDeposit(func(ctx context.Context, deposit *Deposit) error {
+			deposits <- deposit
+			return nil
+		}),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx)
+
+	receive := func() *Deposit {
+		select {
+		case deposit := <-deposits:
+			return deposit
+		case <-time.After(5 * time.Second):
+			t.Fatal("deposit is not received")
+			return nil
+		}
+	}
+	expectNone := func() {
+		select {
+		case deposit := <-deposits:
+			t.Fatalf("unexpected deposit %+v", deposit)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+
+	deposit := receive()
+	if deposit.Hash != "Mt01" || deposit.Height != 1 || deposit.From != bob || deposit.To != alice || deposit.Coin != "MNT" || deposit.Amount.String() != "1000000000000000000" {
+		t.Errorf("unexpected deposit %+v", deposit)
+	}
+	// the block 2 has 2 confirmations only
+	expectNone()
+
+	node.add()
+	deposit = receive()
+	if deposit.Hash != "Mt02" || deposit.Type != TypeMultisend || deposit.Index != 1 || deposit.From != carol || deposit.Coin != "BIP" || deposit.Amount.String() != "100000000000000000000000000" {
+		t.Errorf("unexpected deposit %+v", deposit)
+	}
+	deposit = receive()
+	if deposit.Hash != "Mt03" || deposit.Type != TypeRedeemCheck || deposit.From != carol || deposit.To != alice || deposit.Coin != "CHECK" || deposit.Amount.Int64() != 42 {
+		t.Errorf("unexpected deposit %+v", deposit)
+	}
+
+	// repeated transaction of block 3 is skipped
+	node.add()
+	expectNone()
+
+	w.Watch(bob)
+	node.add(send("Mt06", alice, bob, "MNT", "2"))
+	node.add()
+	node.add()
+	if deposit = receive(); deposit.Hash != "Mt06" || deposit.To != bob {
+		t.Errorf("unexpected deposit %+v", deposit)
+	}
+}
+
+func TestTransfers_invalidAmount(t *testing.T) {
+	tx := send("Mt01", bob, alice, "MNT", "1e18")
+	if _, err := Transfers(&tx); err == nil {
+		t.Error("Transfers want error of invalid amount")
+	}
+}
+
+func TestWatcher_Run_invalidCheck(t *testing.T) {
+	node := &fakeNode{}
+	// check of earlier format without gas coin
+	redeem := api.TransactionResult{
+		Hash: "Mt01",
+		From: alice,
+		Type: TypeRedeemCheck,
+		Data: map[string]interface{}{"raw_check": "Mcf8a38334383002830f423f8a4d4e5400000000000000888ac7230489e80000b841d184caa333fe636288fc68d99dea2c8af5f7db4569a0bb91e03214e7e238f89d2b21f4d2b730ef590fd8de72bd43eb5c6265664df5aa3610ef6c71538d9295ee001ba08bd966fc5a093024a243e62cdc8131969152d21ee9220bc0d95044f54e3dd485a033bc4e03da3ea8a2cd2bd149d16c022ee604298575380db8548b4fd6672a9195", "proof": "00"},
+	}
+	node.add(redeem, send("Mt02", bob, alice, "MNT", "1"))
+
+	deposits := make(chan *Deposit, 10)
+	errs := make(chan error, 10)
+	w := New(node, []string{alice},
+		WithFollowerOptions(follower.WithPollInterval(time.Millisecond)),
+		WithErrorHandler(func(err error) { errs <- err }),
+		OnDeposit(func(ctx context.Context, deposit *Deposit) error {
+			deposits <- deposit
+			return nil
+		}),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx)
+
+	select {
+	case deposit := <-deposits:
+		if deposit.Hash != "Mt02" {
+			t.Errorf("unexpected deposit %+v", deposit)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("deposit is not received")
+	}
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrInvalidCheck) {
+			t.Errorf("error got %v, want ErrInvalidCheck", err)
+		}
+	default:
+		t.Error("error of invalid check is not reported")
+	}
+}